@@ -4,8 +4,10 @@ import (
 	"context"
 	"log"
 	"manager-node/internal/config"
+	grpc_server "manager-node/internal/grpc-server"
 	manager_client "manager-node/internal/manager-client"
 	"manager-node/internal/server"
+	"manager-node/internal/store"
 	"os"
 	"os/signal"
 	"syscall"
@@ -21,9 +23,23 @@ func main() {
 	cfg := config.LoadConfig()
 	// ====================
 
+	// ===== Store =====
+	log.Println("[SERVICE] INITIALIZING STORE")
+	var st store.Store
+	if cfg.StorePath != "" {
+		fileStore, err := store.NewFileStore(cfg.StorePath)
+		if err != nil {
+			log.Fatalln("[STORE][ERROR]:", err)
+		}
+		st = fileStore
+	} else {
+		st = store.NewMemoryStore()
+	}
+	// =====================
+
 	// ===== Manager =====
 	log.Println("[SERVICE] INITIALIZING GENERATOR")
-	manager := manager_client.NewManagerClient(cfg)
+	manager := manager_client.NewManagerClient(cfg, st)
 
 	// =====================
 
@@ -32,14 +48,32 @@ func main() {
 	srv := server.New(cfg, manager)
 	log.Println("[SERVER] Start")
 	srv.Start()
+
+	// gRPC сервис для нод с TRANSPORT=grpc, REST при этом остается
+	var grpcSrv *grpc_server.Server
+	if cfg.GrpcPort != "" {
+		grpcSrv = grpc_server.New(cfg, manager)
+		log.Println("[GRPC SERVER] Start")
+		grpcSrv.Start()
+	}
 	// ====================
 
 	<-stop
 	ctxClose, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
+	if grpcSrv != nil {
+		if err := grpcSrv.Stop(ctxClose); err != nil {
+			log.Println("[GRPC SERVER][ERROR] error while stopping: ", err)
+		}
+	}
 	err := srv.Stop(ctxClose)
 	if err != nil {
 		log.Fatalln("[SERVER][ERROR] error while stopping: ", err)
 	}
 
+	err = st.Close()
+	if err != nil {
+		log.Fatalln("[STORE][ERROR] error while closing: ", err)
+	}
+
 }
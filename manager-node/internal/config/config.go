@@ -10,8 +10,27 @@ import (
 type Config struct {
 	PublicPort  string `envconfig:"PUBLIC_PORT" required:"true"`
 	PrivatePort string `envconfig:"PRIVATE_PORT" required:"true"`
+	GrpcPort    string `envconfig:"GRPC_PORT"` // порт gRPC сервиса для нод с TRANSPORT=grpc, пусто - только REST
 
 	CheckHealthInterval time.Duration `envconfig:"HEALTH_CHECK_INTERVAL" required:"true"`
+	HeartbeatGrace      time.Duration `envconfig:"HEARTBEAT_GRACE" default:"45s"` // нода снимается, если от нее столько не было heartbeat, 0 - не снимать
+	NodeTimeout         time.Duration `envconfig:"NODE_TIMEOUT" default:"30s"`    // таймаут запросов менеджера к мастерам и слейвам
+
+	SubtaskLeaseTimeout  time.Duration `envconfig:"SUBTASK_LEASE_TIMEOUT" default:"5m"`   // за сколько слейв должен вернуть подзадачу
+	SubtaskCheckInterval time.Duration `envconfig:"SUBTASK_CHECK_INTERVAL" default:"30s"` // как часто проверяются аренды подзадач
+
+	SubtaskTargetDuration time.Duration `envconfig:"SUBTASK_TARGET_DURATION" default:"10s"` // желаемое время решения одной подзадачи слейвом
+
+	PullTimeout time.Duration `envconfig:"PULL_TIMEOUT" default:"30s"` // сколько /subtask/next ждет работы для слейва в режиме pull
+
+	StorePath string `envconfig:"STORE_PATH"` // файл журнала состояния, если пусто - состояние хранится только в памяти
+
+	FinishedTaskTTL time.Duration `envconfig:"FINISHED_TASK_TTL" default:"24h"` // сколько хранится статус завершенной задачи, 0 - не удалять
+	BlobTTL         time.Duration `envconfig:"BLOB_TTL" default:"1h"`           // сколько хранится загруженный блоб, на который не сослалась ни одна задача, 0 - не удалять
+
+	ScriptMaxSteps       uint64        `envconfig:"SCRIPT_MAX_STEPS" default:"100000000"`       // лимит шагов скриптов, исполняемых на менеджере при проверке задачи, если в задаче нет своего
+	ScriptTimeout        time.Duration `envconfig:"SCRIPT_TIMEOUT" default:"10s"`               // лимит времени проверки скриптов задачи, если в задаче нет своего
+	ScriptMaxResultBytes int           `envconfig:"SCRIPT_MAX_RESULT_BYTES" default:"10485760"` // лимит размера данных пробного вызова генератора, если в задаче нет своего
 }
 
 func LoadConfig() *Config {
@@ -40,7 +59,23 @@ func (c *Config) PrintConfig() {
 	log.Println("_____________SERVER____________ ")
 	log.Println("PUBLIC_PORT.................... ", c.PublicPort)
 	log.Println("PRIVATE_PORT................... ", c.PrivatePort)
+	log.Println("GRPC_PORT...................... ", c.GrpcPort)
 	log.Println("HEALTH_CHECK_INTERVAL.......... ", c.CheckHealthInterval)
+	log.Println("HEARTBEAT_GRACE................ ", c.HeartbeatGrace)
+	log.Println("NODE_TIMEOUT................... ", c.NodeTimeout)
+	log.Println("_____________SUBTASK___________ ")
+	log.Println("SUBTASK_LEASE_TIMEOUT.......... ", c.SubtaskLeaseTimeout)
+	log.Println("SUBTASK_CHECK_INTERVAL......... ", c.SubtaskCheckInterval)
+	log.Println("SUBTASK_TARGET_DURATION........ ", c.SubtaskTargetDuration)
+	log.Println("PULL_TIMEOUT................... ", c.PullTimeout)
+	log.Println("_____________STORE_____________ ")
+	log.Println("STORE_PATH..................... ", c.StorePath)
+	log.Println("FINISHED_TASK_TTL.............. ", c.FinishedTaskTTL)
+	log.Println("BLOB_TTL....................... ", c.BlobTTL)
+	log.Println("_____________SCRIPT____________ ")
+	log.Println("SCRIPT_MAX_STEPS............... ", c.ScriptMaxSteps)
+	log.Println("SCRIPT_TIMEOUT................. ", c.ScriptTimeout)
+	log.Println("SCRIPT_MAX_RESULT_BYTES........ ", c.ScriptMaxResultBytes)
 
 	log.Println("==================================================")
 }
@@ -0,0 +1,215 @@
+package grpc_server
+
+import (
+	"errors"
+	"io"
+	"log"
+	"protocol"
+	"protocol/gridpb"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errStreamClosed    = errors.New("dispatch stream closed")
+	errRequestTimeout  = errors.New("dispatch request timeout")
+	errUnexpectedReply = errors.New("unexpected dispatch reply")
+)
+
+/*
+Dispatch - поток слейва с TRANSPORT=grpc
+
+Первое сообщение - hello с uuid слейва, после чего поток становится каналом менеджера к слейву
+вместо REST: подзадачи, отмена и статус. Результаты и ошибки подзадач слейв отправляет в этот же поток,
+на каждое сообщение с id менеджер отвечает ack
+*/
+func (s *Server) Dispatch(stream gridpb.Manager_DispatchServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	slaveUuid := first.GetHello().GetSlaveUuid()
+	if slaveUuid == "" {
+		return status.Error(codes.InvalidArgument, "first message must be hello")
+	}
+
+	conn := newSlaveStream(stream, s.Cfg.NodeTimeout)
+	s.managerCli.AttachSlave(slaveUuid, conn)
+	defer func() {
+		s.managerCli.DetachSlave(slaveUuid, conn)
+		close(conn.done)
+	}()
+
+	// Recv не прерывается остановкой сервера, поэтому сообщения читаются отдельно
+	msgs := make(chan *gridpb.SlaveMessage)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case msgs <- msg:
+			case <-conn.done:
+				return
+			}
+		}
+	}()
+
+	for {
+		var msg *gridpb.SlaveMessage
+		select {
+		case msg = <-msgs:
+		case err = <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case <-s.closing:
+			return status.Error(codes.Unavailable, "manager is stopping")
+		}
+
+		switch m := msg.GetMsg().(type) {
+		case *gridpb.SlaveMessage_Ack, *gridpb.SlaveMessage_Status:
+			conn.reply(msg)
+		case *gridpb.SlaveMessage_Complete:
+			// прием результата отправляет его мастеру, поток за это время не должен стоять
+			go func(id uint64, req protocol.CompleteSubtaskRequest) {
+				conn.ack(id, s.managerCli.CompleteSubTask(req))
+			}(msg.GetId(), m.Complete.Protocol())
+		case *gridpb.SlaveMessage_Error:
+			go func(id uint64, req protocol.ErrorSubtaskRequest) {
+				s.reportSubtaskError(req)
+				conn.ack(id, nil)
+			}(msg.GetId(), m.Error.Protocol())
+		default:
+			log.Printf("[DISPATCH][%s] unexpected message %T\n", slaveUuid, m)
+		}
+	}
+}
+
+// slaveStream - поток Dispatch слейва как client.SlaveAPI. Запросы и ответы связываются по id
+type slaveStream struct {
+	stream  gridpb.Manager_DispatchServer
+	sendMu  sync.Mutex    // Send потока не потокобезопасен
+	timeout time.Duration // сколько менеджер ждет ответа слейва на запрос (NODE_TIMEOUT)
+
+	mu      sync.Mutex
+	lastID  uint64
+	pending map[uint64]chan *gridpb.SlaveMessage
+
+	done chan struct{} // закрывается, когда поток завершен
+}
+
+func newSlaveStream(stream gridpb.Manager_DispatchServer, timeout time.Duration) *slaveStream {
+	return &slaveStream{
+		stream:  stream,
+		timeout: timeout,
+		pending: make(map[uint64]chan *gridpb.SlaveMessage),
+		done:    make(chan struct{}),
+	}
+}
+
+func (s *slaveStream) send(msg *gridpb.ManagerMessage) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	return s.stream.Send(msg)
+}
+
+// request - запрос слейву и ожидание ответа с тем же id
+func (s *slaveStream) request(msg *gridpb.ManagerMessage) (*gridpb.SlaveMessage, error) {
+	reply := make(chan *gridpb.SlaveMessage, 1)
+	s.mu.Lock()
+	s.lastID++
+	msg.Id = s.lastID
+	s.pending[msg.Id] = reply
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, msg.Id)
+		s.mu.Unlock()
+	}()
+
+	if err := s.send(msg); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-reply:
+		return r, nil
+	case <-s.done:
+		return nil, errStreamClosed
+	case <-timer.C:
+		return nil, errRequestTimeout
+	}
+}
+
+// reply - ответ слейва на запрос менеджера. Ответ на запрос, который уже не ждут, отбрасывается
+func (s *slaveStream) reply(msg *gridpb.SlaveMessage) {
+	s.mu.Lock()
+	ch, ok := s.pending[msg.GetId()]
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case ch <- msg:
+	default:
+	}
+}
+
+// ack - ответ менеджера на результат или ошибку подзадачи от слейва
+func (s *slaveStream) ack(id uint64, err error) {
+	if id == 0 {
+		return
+	}
+
+	err = s.send(&gridpb.ManagerMessage{Id: id, Msg: &gridpb.ManagerMessage_Ack{Ack: gridpb.NewAck(err)}})
+	if err != nil {
+		log.Println("[DISPATCH][ERROR] send ack:", err)
+	}
+}
+
+// requestAck - запрос, на который слейв отвечает ack
+func (s *slaveStream) requestAck(msg *gridpb.ManagerMessage) error {
+	r, err := s.request(msg)
+	if err != nil {
+		return err
+	}
+	if r.GetAck() == nil {
+		return errUnexpectedReply
+	}
+
+	return r.GetAck().Err()
+}
+
+func (s *slaveStream) AddTask(req protocol.ComputeRequest) error {
+	return s.requestAck(&gridpb.ManagerMessage{Msg: &gridpb.ManagerMessage_Compute{Compute: gridpb.NewComputeRequest(req)}})
+}
+
+func (s *slaveStream) Cancel(subtaskUUID string) error {
+	return s.requestAck(&gridpb.ManagerMessage{Msg: &gridpb.ManagerMessage_Cancel{Cancel: &gridpb.CancelSubtask{SubtaskUuid: subtaskUUID}}})
+}
+
+func (s *slaveStream) CheckStatus() (string, error) {
+	r, err := s.request(&gridpb.ManagerMessage{Msg: &gridpb.ManagerMessage_Status{Status: &gridpb.StatusRequest{}}})
+	if err != nil {
+		return "", err
+	}
+	if r.GetStatus() == nil {
+		return "", errUnexpectedReply
+	}
+
+	return r.GetStatus().GetStatus(), nil
+}
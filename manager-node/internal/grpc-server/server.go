@@ -0,0 +1,182 @@
+package grpc_server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"manager-node/internal/config"
+	manager_client "manager-node/internal/manager-client"
+	"net"
+	"protocol"
+	"protocol/gridpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Server - gRPC сервис менеджера (GRPC_PORT). Методы повторяют REST обработчики, REST API остается доступным
+type Server struct {
+	gridpb.UnimplementedManagerServer
+
+	GrpcServer *grpc.Server
+	Cfg        *config.Config
+	managerCli *manager_client.ManagerClient
+
+	closing chan struct{} // закрывается при остановке, потоки Dispatch завершаются сами
+}
+
+func New(cfg *config.Config, managerCli *manager_client.ManagerClient) *Server {
+	s := &Server{
+		GrpcServer: grpc.NewServer(
+			grpc.MaxRecvMsgSize(gridpb.MAX_MESSAGE_SIZE),
+			grpc.MaxSendMsgSize(gridpb.MAX_MESSAGE_SIZE),
+		),
+		Cfg:        cfg,
+		managerCli: managerCli,
+		closing:    make(chan struct{}),
+	}
+	gridpb.RegisterManagerServer(s.GrpcServer, s)
+
+	return s
+}
+
+func (s *Server) Start() {
+	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0%s", s.Cfg.GrpcPort))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		if err := s.GrpcServer.Serve(lis); err != nil {
+			log.Fatal(err)
+		}
+	}()
+}
+
+// Stop - остановка сервиса: потоки Dispatch закрываются, начатые вызовы дорабатывают до истечения ctx
+func (s *Server) Stop(ctx context.Context) error {
+	close(s.closing)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GrpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.GrpcServer.Stop()
+	}
+	log.Println("[SERVER][STOP] gRPC server stopped")
+
+	return nil
+}
+
+// remoteIP - адрес, с которого пришел вызов, для регистрации ноды
+func remoteIP(ctx context.Context) net.IP {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if addr, ok := p.Addr.(*net.TCPAddr); ok {
+		return addr.IP
+	}
+
+	return nil
+}
+
+func (s *Server) RegisterMaster(ctx context.Context, req *gridpb.Node) (*gridpb.Empty, error) {
+	node := req.Protocol()
+	node.Url = manager_client.ResolveNodeAddr(node.Url, remoteIP(ctx))
+
+	return &gridpb.Empty{}, gridpb.Status(s.managerCli.RegisterMaster(node))
+}
+
+func (s *Server) RegisterSlave(ctx context.Context, req *gridpb.Node) (*gridpb.Empty, error) {
+	node := req.Protocol()
+	node.Url = manager_client.ResolveNodeAddr(node.Url, remoteIP(ctx))
+
+	return &gridpb.Empty{}, gridpb.Status(s.managerCli.RegisterSlave(node))
+}
+
+func (s *Server) RemoveNode(ctx context.Context, req *gridpb.RemoveNodeRequest) (*gridpb.Empty, error) {
+	if req.GetUuid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "node uuid is required")
+	}
+
+	err := s.managerCli.RemoveNode(req.GetUuid(), req.GetDrain())
+	if errors.Is(err, manager_client.ErrNodeNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &gridpb.Empty{}, gridpb.Status(err)
+}
+
+func (s *Server) Heartbeat(ctx context.Context, req *gridpb.Heartbeat) (*gridpb.Empty, error) {
+	return &gridpb.Empty{}, gridpb.Status(s.managerCli.Heartbeat(req.Protocol()))
+}
+
+func (s *Server) AddTask(ctx context.Context, req *gridpb.TaskConfig) (*gridpb.AddTaskResponse, error) {
+	taskUuid, err := s.managerCli.SetTask(req.Protocol())
+	if err != nil {
+		return nil, gridpb.Status(err)
+	}
+
+	return &gridpb.AddTaskResponse{TaskUuid: taskUuid}, nil
+}
+
+func (s *Server) CloseTask(ctx context.Context, req *gridpb.TaskRef) (*gridpb.Empty, error) {
+	if req.GetTaskUuid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "task uuid is required")
+	}
+
+	return &gridpb.Empty{}, gridpb.Status(s.managerCli.CloseTask(req.GetTaskUuid()))
+}
+
+func (s *Server) TaskStatus(ctx context.Context, req *gridpb.TaskRef) (*gridpb.TaskStatus, error) {
+	if req.GetTaskUuid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "task uuid is required")
+	}
+
+	taskStatus, err := s.managerCli.GetTaskStatus(req.GetTaskUuid())
+	if err != nil {
+		return nil, gridpb.Status(err)
+	}
+
+	return gridpb.NewTaskStatus(taskStatus), nil
+}
+
+func (s *Server) CompleteSubtask(ctx context.Context, req *gridpb.CompleteSubtaskRequest) (*gridpb.Empty, error) {
+	return &gridpb.Empty{}, gridpb.Status(s.managerCli.CompleteSubTask(req.Protocol()))
+}
+
+func (s *Server) ErrorSubtask(ctx context.Context, req *gridpb.ErrorSubtaskRequest) (*gridpb.Empty, error) {
+	s.reportSubtaskError(req.Protocol())
+
+	return &gridpb.Empty{}, nil
+}
+
+// reportSubtaskError - ошибка подзадачи от слейва, без типа считается ошибкой скрипта
+func (s *Server) reportSubtaskError(req protocol.ErrorSubtaskRequest) {
+	if req.Type == "" {
+		req.Type = protocol.ERROR_SCRIPT
+	}
+	s.managerCli.ReportSubtaskError(req.SubtaskUUID, req.SlaveUUID, req.Type, req.Error)
+}
+
+func (s *Server) GetBlob(ctx context.Context, req *gridpb.BlobRef) (*gridpb.Blob, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "blob id is required")
+	}
+
+	data, err := s.managerCli.GetBlob(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &gridpb.Blob{Data: data}, nil
+}
@@ -0,0 +1,150 @@
+package manager_client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"protocol"
+	"time"
+)
+
+/*
+Хранилище блобов (скриптов и входных данных задач) с адресацией по содержимому: id блоба - sha256 его байт.
+
+Подзадачи несут только id блобов, слейв скачивает каждый блоб один раз через /blob и держит его в своем кэше.
+Блоб живет, пока на него ссылается хотя бы одна активная задача. Блобы, загруженные мастером заранее,
+хранятся до первой задачи, которая на них сошлется, но не дольше 'BLOB_TTL' от последней загрузки
+*/
+type blob struct {
+	data  []byte
+	refs  int       // кол-во активных задач, ссылающихся на блоб
+	putAt time.Time // время последней загрузки мастером
+}
+
+// blobID - адрес блоба по его содержимому
+func blobID(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// PutBlob - загрузка блоба, возвращает его id
+func (mc *ManagerClient) PutBlob(data []byte) string {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	id := blobID(data)
+	b, ok := mc.blobs[id]
+	if !ok {
+		b = &blob{data: append([]byte(nil), data...)}
+		mc.blobs[id] = b
+	}
+	b.putAt = time.Now()
+
+	return id
+}
+
+// blobWorker - удаление блобов без ссылок, загруженных раньше 'BLOB_TTL'
+func (mc *ManagerClient) blobWorker() {
+	interval := mc.cfg.BlobTTL / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deadline := time.Now().Add(-mc.cfg.BlobTTL)
+
+		mc.mu.Lock()
+		for id, b := range mc.blobs {
+			if b.refs <= 0 && b.putAt.Before(deadline) {
+				delete(mc.blobs, id)
+				log.Printf("[BLOB] blob %s expired, uploaded %s\n", id, b.putAt.Format(time.RFC3339))
+			}
+		}
+		mc.mu.Unlock()
+	}
+}
+
+// GetBlob - блоб по id
+func (mc *ManagerClient) GetBlob(id string) ([]byte, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	return mc.blobData(id, "requested")
+}
+
+// retainBlob - задача ссылается на блоб, вызывается под mc.mu
+func (mc *ManagerClient) retainBlob(data []byte) string {
+	id := blobID(data)
+	b, ok := mc.blobs[id]
+	if !ok {
+		b = &blob{data: data}
+		mc.blobs[id] = b
+	}
+	b.refs++
+
+	return id
+}
+
+// releaseBlob - задача больше не ссылается на блоб, вызывается под mc.mu
+func (mc *ManagerClient) releaseBlob(id string) {
+	b, ok := mc.blobs[id]
+	if !ok {
+		return
+	}
+	b.refs--
+	if b.refs <= 0 {
+		delete(mc.blobs, id)
+	}
+}
+
+// retainTaskBlobs - скрипты и данные задачи становятся блобами, в подзадачах передаются только их id
+func (mc *ManagerClient) retainTaskBlobs(task *Task) {
+	task.generatorScript.Hash = mc.retainBlob([]byte(task.generatorScript.Script))
+	task.computeScript.Hash = mc.retainBlob([]byte(task.computeScript.Script))
+	task.dataRef = mc.retainBlob(task.Data)
+}
+
+func (mc *ManagerClient) releaseTaskBlobs(task *Task) {
+	mc.releaseBlob(task.generatorScript.Hash)
+	mc.releaseBlob(task.computeScript.Hash)
+	mc.releaseBlob(task.dataRef)
+}
+
+// resolveTaskBlobs - мастер может передать вместо скриптов и данных id ранее загруженных блобов, вызывается под mc.mu
+func (mc *ManagerClient) resolveTaskBlobs(taskCfg *protocol.TaskConfig) error {
+	if taskCfg.GeneratorScript.Script == "" && taskCfg.GeneratorScript.Hash != "" {
+		data, err := mc.blobData(taskCfg.GeneratorScript.Hash, "generator script")
+		if err != nil {
+			return err
+		}
+		taskCfg.GeneratorScript.Script = string(data)
+	}
+	if taskCfg.ComputeScript.Script == "" && taskCfg.ComputeScript.Hash != "" {
+		data, err := mc.blobData(taskCfg.ComputeScript.Hash, "compute script")
+		if err != nil {
+			return err
+		}
+		taskCfg.ComputeScript.Script = string(data)
+	}
+	if len(taskCfg.Data) == 0 && taskCfg.DataRef != "" {
+		data, err := mc.blobData(taskCfg.DataRef, "data")
+		if err != nil {
+			return err
+		}
+		taskCfg.Data = data
+	}
+
+	return nil
+}
+
+func (mc *ManagerClient) blobData(id string, what string) ([]byte, error) {
+	b, ok := mc.blobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%s blob %s not found", what, id)
+	}
+
+	return b.data, nil
+}
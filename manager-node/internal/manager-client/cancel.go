@@ -0,0 +1,55 @@
+package manager_client
+
+import (
+	"log"
+)
+
+/*
+takeTaskSubtasks - снятие всех подзадач отмененной задачи. Вызывается под mc.mu
+
+Подзадачи удаляются из учета, их слоты освобождаются (слейв не пришлет по ним результат),
+а отправленные слейвам возвращаются, чтобы им можно было отправить отмену
+*/
+func (mc *ManagerClient) takeTaskSubtasks(taskUuid string) []Subtask {
+	var sent []Subtask
+	for uuid, subtask := range mc.subtasksStatus {
+		if subtask.TaskUuid != taskUuid {
+			continue
+		}
+		delete(mc.subtasksStatus, uuid)
+		mc.forgetSubtask(uuid)
+		if subtask.status == SUBTASK_SENT {
+			mc.releaseSlot(subtask.SlaveNodeUuid)
+			sent = append(sent, subtask)
+		}
+	}
+
+	return sent
+}
+
+// cancelSubtasks - отправка отмены подзадач слейвам, чтобы они не тратили ресурсы на брошенную задачу
+func (mc *ManagerClient) cancelSubtasks(subtasks []Subtask) {
+	for _, subtask := range subtasks {
+		mc.mu.Lock()
+		slave, ok := mc.SlaveNodes[subtask.SlaveNodeUuid]
+		if ok && slave.Pull {
+			// до слейва в режиме pull менеджер не достучится, отмена уйдет ему в ответе на длинный опрос
+			slave.cancels = append(slave.cancels, subtask.uuid)
+			mc.notifyWork()
+		}
+		mc.mu.Unlock()
+		if !ok || slave.Pull {
+			continue
+		}
+
+		err := mc.sendSlaveCancel(slave, subtask.uuid)
+		if err != nil {
+			log.Printf("[CANCEL SUBTASK][TASK | %s][SUBTASK | %s][SLAVE | %s] error: %v\n", subtask.TaskUuid, subtask.uuid, slave.UUID, err)
+		}
+	}
+}
+
+// sendSlaveCancel - отмена подзадачи на слейве
+func (mc *ManagerClient) sendSlaveCancel(node *SlaveNode, subtaskUuid string) error {
+	return mc.slaveClient(node).Cancel(subtaskUuid)
+}
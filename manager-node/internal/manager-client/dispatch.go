@@ -0,0 +1,32 @@
+package manager_client
+
+import (
+	"log"
+	"protocol/client"
+)
+
+/*
+AttachSlave - слейв подключился потоком Dispatch (TRANSPORT=grpc)
+
+Пока поток открыт, подзадачи, отмена и запрос статуса идут слейву через него, а не по REST.
+Поток живет независимо от регистрации: перерегистрация и drain его не закрывают
+*/
+func (mc *ManagerClient) AttachSlave(slaveUuid string, conn client.SlaveAPI) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.streams[slaveUuid] = conn
+	log.Printf("[DISPATCH] slave %s connected\n", slaveUuid)
+}
+
+// DetachSlave - поток слейва закрыт. Если слейв уже открыл новый поток, он остается
+func (mc *ManagerClient) DetachSlave(slaveUuid string, conn client.SlaveAPI) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.streams[slaveUuid] != conn {
+		return
+	}
+	delete(mc.streams, slaveUuid)
+	log.Printf("[DISPATCH] slave %s disconnected\n", slaveUuid)
+}
@@ -0,0 +1,85 @@
+package manager_client
+
+import (
+	"log"
+	"protocol"
+)
+
+/*
+exhaustTask - генератор сообщил об окончании данных на позиции end. Вызывается под mc.mu
+
+Новые диапазоны задаче больше не выдаются, а подзадачи за концом данных снимаются:
+они бы тоже вернули "empty". Если конец данных уже известен, берется меньшая позиция.
+Возвращаются снятые подзадачи, отправленные слейвам, чтобы им можно было отправить отмену
+*/
+func (mc *ManagerClient) exhaustTask(task *Task, end uint32) []Subtask {
+	if task.exhausted && end >= task.end {
+		return nil
+	}
+	task.exhausted = true
+	task.end = end
+
+	var sent []Subtask
+	for uuid, subtask := range mc.subtasksStatus {
+		if subtask.TaskUuid != task.uuid || subtask.start < end || subtask.status == SUBTASK_DELIVERING {
+			continue
+		}
+		delete(mc.subtasksStatus, uuid)
+		mc.forgetSubtask(uuid)
+		task.dropped++
+		if subtask.status == SUBTASK_SENT {
+			mc.releaseSlot(subtask.SlaveNodeUuid)
+			sent = append(sent, subtask)
+		}
+	}
+	mc.persistProgress(task)
+
+	log.Printf("[TASK EXHAUSTED][TASK | %s] end of data at %d, dropped subtasks: %d\n", task.uuid, end, task.dropped)
+
+	return sent
+}
+
+/*
+finishTask - завершение задачи, если все ее диапазоны до конца данных решены
+
+Задача завершается, только когда генератор сообщил об окончании данных и у задачи не осталось
+ни одной подзадачи: в работе у слейвов, в ожидании повторной отправки или в доставке мастеру.
+Неустранимая ошибка подзадачи завершает задачу раньше, через alertTaskError
+*/
+func (mc *ManagerClient) finishTask(uuid string) {
+	mc.mu.Lock()
+	task, ok := mc.taskStatus[uuid]
+	if !ok || !task.exhausted {
+		mc.mu.Unlock()
+		return
+	}
+	for _, subtask := range mc.subtasksStatus {
+		if subtask.TaskUuid == uuid {
+			mc.mu.Unlock()
+			return
+		}
+	}
+
+	mc.removeTask(uuid, STATUS_DONE)
+	summary := protocol.TaskSummary{
+		TaskUUID:  uuid,
+		End:       task.end,
+		Completed: task.completed,
+		Failed:    task.failed,
+		Dropped:   task.dropped,
+	}
+	master, ok := mc.MasterNodes[task.MasterUuid]
+	mc.mu.Unlock()
+
+	if !ok {
+		log.Printf("[DONE TASK][ERROR] master node not found with uuid %s\n", task.MasterUuid)
+		return
+	}
+
+	err := mc.masterClient(master).TaskDone(summary)
+	if err != nil {
+		log.Println("[DONE TASK][ERROR]", err)
+	}
+
+	log.Printf("[TASK DONE] task: %s, end: %d, completed: %d, failed: %d, dropped: %d\n", uuid, summary.End, summary.Completed, summary.Failed, summary.Dropped)
+}
@@ -0,0 +1,45 @@
+package manager_client
+
+import (
+	"log"
+	"protocol"
+	"time"
+)
+
+/*
+finish - у завершенной задачи остаются только статус и счетчики для /task/status.
+
+Входные данные и скрипты больше не нужны и не держатся в памяти до истечения 'FINISHED_TASK_TTL'
+*/
+func (t *Task) finish() {
+	t.Data = nil
+	t.dataRef = ""
+	t.generatorScript = protocol.ScriptConfig{}
+	t.computeScript = protocol.ScriptConfig{}
+	t.cancelTask = nil
+	t.finishedAt = time.Now()
+}
+
+// finishedTaskWorker - удаление завершенных задач старше 'FINISHED_TASK_TTL' из памяти и хранилища
+func (mc *ManagerClient) finishedTaskWorker() {
+	interval := mc.cfg.FinishedTaskTTL / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deadline := time.Now().Add(-mc.cfg.FinishedTaskTTL)
+
+		mc.mu.Lock()
+		for uuid, task := range mc.finishedTasks {
+			if task.finishedAt.Before(deadline) {
+				delete(mc.finishedTasks, uuid)
+				mc.forgetTask(uuid)
+				log.Printf("[FINISHED TASK] task %s expired, finished %s\n", uuid, task.finishedAt.Format(time.RFC3339))
+			}
+		}
+		mc.mu.Unlock()
+	}
+}
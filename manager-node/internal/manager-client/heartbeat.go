@@ -0,0 +1,71 @@
+package manager_client
+
+import (
+	"log"
+	"protocol"
+	"time"
+)
+
+/*
+Heartbeat - сигнал жизни от мастера или слейва
+
+Если нода не известна менеджеру (ее не удалось зарегистрировать при старте, или менеджер
+перезапущен без хранилища), возвращается protocol.ErrUnknownNode
+*/
+func (mc *ManagerClient) Heartbeat(hb protocol.Heartbeat) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	now := time.Now()
+	switch hb.Kind {
+	case protocol.NODE_KIND_MASTER:
+		master, ok := mc.MasterNodes[hb.UUID]
+		if !ok {
+			return protocol.ErrUnknownNode
+		}
+		master.lastSeen, master.heartbeat = now, hb
+	case protocol.NODE_KIND_SLAVE:
+		slave, ok := mc.SlaveNodes[hb.UUID]
+		if !ok {
+			return protocol.ErrUnknownNode
+		}
+		slave.lastSeen, slave.heartbeat = now, hb
+	default:
+		return protocol.ErrUnknownNode
+	}
+
+	return nil
+}
+
+/*
+heartbeatWorker - снятие нод, от которых не было heartbeat дольше 'HEARTBEAT_GRACE'
+
+Задачи снятого мастера завершаются с ошибкой, подзадачи снятого слейва отдаются другим слейвам
+*/
+func (mc *ManagerClient) heartbeatWorker() {
+	interval := mc.cfg.HeartbeatGrace / 3
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deadline := time.Now().Add(-mc.cfg.HeartbeatGrace)
+
+		mc.mu.Lock()
+		for uuid, master := range mc.MasterNodes {
+			if master.lastSeen.Before(deadline) {
+				mc.dropMaster(uuid)
+				log.Printf("[HEARTBEAT] master %s expired, last seen %s\n", uuid, master.lastSeen.Format(time.RFC3339))
+			}
+		}
+		for uuid, slave := range mc.SlaveNodes {
+			if slave.lastSeen.Before(deadline) {
+				requeued := mc.dropSlave(uuid)
+				log.Printf("[HEARTBEAT] slave %s expired, last seen %s, subtasks requeued: %d\n", uuid, slave.lastSeen.Format(time.RFC3339), requeued)
+			}
+		}
+		mc.mu.Unlock()
+	}
+}
@@ -1,17 +1,17 @@
 package manager_client
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	uuid2 "github.com/google/uuid"
-	"io"
 	"log"
 	"manager-node/internal/config"
-	"manager-node/pkg/model"
-	"net/http"
+	"manager-node/internal/script"
+	"manager-node/internal/store"
+	"protocol"
+	"protocol/client"
 	"sync"
 	"time"
 )
@@ -30,6 +30,22 @@ var statusStr = []string{
 	"done",
 }
 
+// статусы подзадачи
+const (
+	SUBTASK_WAIT       uint8 = iota // ждет свободного слейва (после ошибки или рестарта менеджера)
+	SUBTASK_SENT                    // отправлена слейву
+	SUBTASK_DELIVERING              // решена, результат отправляется мастеру
+)
+
+// статусы слейва
+const (
+	SLAVE_OK       = "ok"
+	SLAVE_SUSPECT  = "suspect"  // не вернул подзадачу за время аренды
+	SLAVE_DRAINING = "draining" // дорешивает отправленные подзадачи перед остановкой, новых не получает
+
+	SLAVE_STATUS_WAIT_TASK = "waiting task" // ответ /checkStatus свободного слейва
+)
+
 const (
 	errSubtaskThreshold = 3
 	defaultSlavePower   = 50
@@ -40,38 +56,86 @@ type ManagerClient struct {
 	SlaveNodes  map[string]*SlaveNode
 	cfg         *config.Config
 
-	FreeSlaves map[string]struct{} // свободные слейвы
-	WorkSlaves map[string]struct{} // слейвы в работе
+	FreeSlaves map[string]int // свободные слоты слейвов
+	WorkSlaves map[string]int // занятые слоты слейвов
 
-	taskStatus     map[string]Task    // общие задачи
+	taskStatus     map[string]*Task   // общие задачи, ключ - uuid задачи
+	finishedTasks  map[string]*Task   // завершенные задачи (done/error), хранятся для отдачи статуса 'FINISHED_TASK_TTL'
 	subtasksStatus map[string]Subtask // подзадачи
 
+	blobs map[string]*blob // скрипты и входные данные задач по sha256
+
+	streams map[string]client.SlaveAPI // потоки Dispatch слейвов, подключенных по gRPC
+
+	workReady chan struct{} // закрывается, когда появляется работа для слейвов в режиме pull
+
+	store store.Store // хранилище состояния, из которого менеджер восстанавливается после рестарта
+
 	mu sync.Mutex
 }
 
-func NewManagerClient(cfg *config.Config) *ManagerClient {
+func NewManagerClient(cfg *config.Config, st store.Store) *ManagerClient {
 	mc := &ManagerClient{
 		MasterNodes:    make(map[string]*MasterNode),
 		SlaveNodes:     make(map[string]*SlaveNode),
-		taskStatus:     make(map[string]Task),
+		taskStatus:     make(map[string]*Task),
+		finishedTasks:  make(map[string]*Task),
 		subtasksStatus: make(map[string]Subtask),
-		FreeSlaves:     make(map[string]struct{}),
-		WorkSlaves:     make(map[string]struct{}),
+		blobs:          make(map[string]*blob),
+		streams:        make(map[string]client.SlaveAPI),
+		workReady:      make(chan struct{}),
+		FreeSlaves:     make(map[string]int),
+		WorkSlaves:     make(map[string]int),
+		store:          st,
 		cfg:            cfg,
 	}
 
+	if err := mc.restore(); err != nil {
+		log.Println("[STORE][ERROR] restore state:", err)
+	}
+
 	go mc.checkMasterHealthWorker() // воркер проверки жизни Мастер-нод
 	go mc.checkSlaveHealthWorker()  // воркер проверки жизни Слейв-нод
 
 	go mc.subtaskWorker() // воркер проверки статусов выполнения подзадач
 
+	if cfg.HeartbeatGrace > 0 {
+		go mc.heartbeatWorker() // воркер снятия нод без heartbeat
+	}
+
+	if cfg.FinishedTaskTTL > 0 {
+		go mc.finishedTaskWorker() // воркер удаления старых завершенных задач
+	}
+
+	if cfg.BlobTTL > 0 {
+		go mc.blobWorker() // воркер удаления блобов, на которые так и не сослалась задача
+	}
+
 	return mc
 }
 
+// Task - задача мастера. У каждой задачи свой uuid, свой цикл планирования и своя функция отмены
 type Task struct {
-	MasterUuid string
-	Data       json.RawMessage
-	counter    uint32
+	uuid            string
+	MasterUuid      string
+	Data            json.RawMessage
+	dataRef         string // id блоба с Data
+	generatorScript protocol.ScriptConfig
+	computeScript   protocol.ScriptConfig
+	taskName        string
+	limits          protocol.ExecutionLimits
+	status          uint8
+	counter         uint32
+	completed       int    // кол-во решенных подзадач
+	failed          int    // кол-во неудачных попыток решения подзадач
+	exhausted       bool   // генератор сообщил об окончании данных, новые диапазоны не выдаются
+	end             uint32 // позиция окончания данных, если exhausted
+	dropped         int    // снятые подзадачи за концом данных
+	sized           bool   // размер задачи известен заранее, диапазоны выдаются только в [0, size)
+	size            uint32
+	solved          uint64 // сумма диапазонов, результаты которых приняты мастером
+	cancelTask      context.CancelFunc
+	finishedAt      time.Time // время завершения задачи, от него отсчитывается 'FINISHED_TASK_TTL'
 }
 
 func (t *Task) GetNext() {
@@ -92,38 +156,46 @@ type Subtask struct {
 }
 
 type MasterNode struct {
-	model.Node
-	generatorScript model.ScriptConfig
-	computeScript   model.ScriptConfig
-	taskName        string
-	statusStr       string
-	status          uint8
-	task            Task
-	cancelTask      context.CancelFunc
-}
+	protocol.Node
+	tasks     map[string]struct{} // uuid задач, поставленных мастером
+	lastSeen  time.Time           // регистрация или последний heartbeat
+	heartbeat protocol.Heartbeat
 
-type TaskConfig struct {
-	MasterUUID      string             `json:"MasterUUID"`
-	GeneratorScript model.ScriptConfig `json:"GeneratorScript"`
-	ComputeScript   model.ScriptConfig `json:"ComputeScript"`
-	Data            json.RawMessage    `json:"Data"`
-	taskName        string
-	task            Task
+	grpc     *client.GRPCMaster // соединение с gRPC сервисом мастера, если он зарегистрировался с GrpcPort
+	grpcAddr string
 }
 
-type ScriptConfig struct {
-	Script   string `json:"Script"`
-	FuncName string `json:"FuncName"`
+// closeGrpc - закрытие соединения с мастером. Вызывается под mc.mu
+func (m *MasterNode) closeGrpc() {
+	if m.grpc == nil {
+		return
+	}
+	if err := m.grpc.Close(); err != nil {
+		log.Printf("[MASTER][ERROR] close grpc %s: %v\n", m.grpcAddr, err)
+	}
+	m.grpc, m.grpcAddr = nil, ""
 }
 
 type SlaveNode struct {
-	model.Node
-	status string
-	power  uint32
+	protocol.Node
+	status     string
+	power      uint32  // кол-во элементов в подзадаче для этого слейва, 0 - defaultSlavePower
+	throughput float64 // измеренная скорость решения, элементов/сек
+	lastSeen   time.Time
+	heartbeat  protocol.Heartbeat
+	cancels    []string // отмены подзадач слейва в режиме pull, уходят ему в ответе /subtask/next
+}
+
+type subtaskAssign struct {
+	subtaskUuid string
+	slave       *SlaveNode
+	amount      uint32
+	start       uint32
 }
 
 func (mc *ManagerClient) taskWorker(ctx context.Context, uuid string) {
 	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
 
 	for {
 		select {
@@ -131,143 +203,222 @@ func (mc *ManagerClient) taskWorker(ctx context.Context, uuid string) {
 			return
 
 		case <-ticker.C:
-			if task, ok := mc.taskStatus[uuid]; ok {
-
-				for slaveUuid, _ := range mc.FreeSlaves {
-					if slave, okS := mc.SlaveNodes[slaveUuid]; okS {
-						mc.mu.Lock()
-						amount := slave.power
-						if amount == 0 {
-							amount = defaultSlavePower
-						}
-						start := task.counter
-						task.counter += amount
-						mc.taskStatus[uuid] = task
+			mc.mu.Lock()
+			task, ok := mc.taskStatus[uuid]
+			if !ok {
+				mc.mu.Unlock()
+				log.Printf("[taskWorker][%s] task not found. Task finished\n", uuid)
+				mc.alertTaskError(uuid, "task not found")
+				return
+			}
 
-						delete(mc.FreeSlaves, slaveUuid)
+			// сначала раздаются подзадачи, ожидающие повторной отправки, затем новые диапазоны
+			var waiting []Subtask
+			for _, subtask := range mc.subtasksStatus {
+				if subtask.TaskUuid == uuid && subtask.status == SUBTASK_WAIT {
+					waiting = append(waiting, subtask)
+				}
+			}
 
-						mc.WorkSlaves[slaveUuid] = struct{}{}
-						mc.mu.Unlock()
+			if task.exhausted && len(waiting) == 0 {
+				// новых диапазонов нет, задача ждет результатов выданных подзадач
+				mc.mu.Unlock()
+				mc.finishTask(uuid)
+				continue
+			}
 
-						mc.sendSubTask(uuid2.NewString(), uuid, slave, amount, start)
+			var assigns []subtaskAssign
+			for slaveUuid, free := range mc.FreeSlaves {
+				slave, okS := mc.SlaveNodes[slaveUuid]
+				if !okS {
+					delete(mc.FreeSlaves, slaveUuid)
+					continue
+				}
+				if slave.Pull {
+					// слейв сам заберет подзадачу через /subtask/next
+					continue
+				}
 
-					}
+				// по одной подзадаче на каждый свободный слот слейва
+				for ; free > 0 && (len(waiting) != 0 || !task.exhausted); free-- {
+					var a subtaskAssign
+					a, waiting = mc.assignNext(task, waiting, slave)
+					assigns = append(assigns, a)
 				}
+			}
+			if len(assigns) != 0 {
+				task.status = STATUS_SOLVING
+				mc.persistProgress(task)
+			}
+			mc.mu.Unlock()
 
-			} else {
-				log.Printf("[taskWorker][%s] task not found. Task finished\n", uuid)
-				mc.alertTaskError(uuid, "task not found")
-				return
+			for _, a := range assigns {
+				mc.sendSubTask(a.subtaskUuid, uuid, a.slave, a.amount, a.start)
 			}
+		}
+	}
+
+}
 
+/*
+assignNext - следующая подзадача задачи для слейва: сначала ожидающая повторной отправки, затем новый диапазон.
+Занимает слот слейва, возвращает оставшиеся ожидающие подзадачи. Вызывается под mc.mu
+*/
+func (mc *ManagerClient) assignNext(task *Task, waiting []Subtask, slave *SlaveNode) (subtaskAssign, []Subtask) {
+	var a subtaskAssign
+	if len(waiting) != 0 {
+		subtask := waiting[0]
+		waiting = waiting[1:]
+		subtask.status = SUBTASK_SENT
+		subtask.SlaveNodeUuid = slave.UUID
+		mc.subtasksStatus[subtask.uuid] = subtask
+		a = subtaskAssign{subtaskUuid: subtask.uuid, slave: slave, amount: subtask.amount, start: subtask.start}
+	} else {
+		amount := slave.power
+		if amount == 0 {
+			amount = defaultSlavePower
 		}
+		if task.sized && task.size-task.counter <= amount {
+			// последний диапазон задачи, дальше выдавать нечего
+			amount = task.size - task.counter
+			task.exhausted = true
+			task.end = task.size
+		}
+		a = subtaskAssign{subtaskUuid: uuid2.NewString(), slave: slave, amount: amount, start: task.counter}
+		task.counter += amount
 	}
 
+	mc.takeSlot(slave.UUID)
+
+	return a, waiting
 }
 
 func (mc *ManagerClient) sendSubTask(subtaskUuid string, taskUuid string, slave *SlaveNode, amount, start uint32) {
-	var subtask Subtask
 	mc.mu.Lock()
-	if _, ok := mc.subtasksStatus[subtaskUuid]; !ok {
-		subtask = Subtask{
-			uuid:          subtaskUuid,
-			TaskUuid:      taskUuid,
-			SlaveNodeUuid: slave.Uuid,
-			Url:           slave.Url + slave.PublicPort,
-			start:         start,
-			amount:        amount,
-			errCount:      0,
-		}
-		mc.subtasksStatus[subtaskUuid] = subtask
-	} else {
-		subtask = mc.subtasksStatus[subtaskUuid]
-		subtask.Url = slave.Url + slave.PublicPort
-		mc.subtasksStatus[subtaskUuid] = subtask
-	}
+	reqBody, refBody, ok := mc.leaseSubtask(subtaskUuid, taskUuid, slave, amount, start)
 	mc.mu.Unlock()
-
-	task := mc.taskStatus[subtask.TaskUuid]
-	mn, ok := mc.MasterNodes[task.MasterUuid]
 	if !ok {
-		mc.alertTaskError(taskUuid, "master node not found")
+		return
 	}
 
-	reqBody := model.ComputeRequest{
-		UuidSubtask: subtaskUuid,
-		Generate:    mn.generatorScript,
-		Compute:     mn.computeScript,
-		Data:        task.Data,
-		Amount:      amount,
-		Start:       start,
+	err := mc.sendSlave(refBody, slave)
+	if errors.Is(err, protocol.ErrBlobNotCached) {
+		log.Printf("[SEND SUBTASK] slave %s can't get blobs, resend subtask %s with source\n", slave.UUID, subtaskUuid)
+		err = mc.sendSlave(reqBody, slave)
+	}
+	if errors.Is(err, protocol.ErrDraining) {
+		// слейв останавливается и подзадачу не взял: это не ошибка подзадачи, она отдается другим слейвам
+		mc.mu.Lock()
+		if s, ok := mc.SlaveNodes[slave.UUID]; ok {
+			s.status = SLAVE_DRAINING
+			delete(mc.FreeSlaves, slave.UUID)
+		}
+		_, requeued := mc.requeueSubtask(subtaskUuid, slave.UUID)
+		mc.mu.Unlock()
+		if requeued {
+			log.Printf("[SEND SUBTASK] slave %s is draining, subtask %s requeued\n", slave.UUID, subtaskUuid)
+		}
+		return
 	}
-	err := mc.sendSlave(reqBody, slave)
 	if err != nil {
 		log.Println(err)
-		mc.AlertSubtaskError(subtaskUuid, slave.Uuid, "error send subtask to slave")
+		mc.AlertSubtaskError(subtaskUuid, slave.UUID, "error send subtask to slave")
 	}
 
 }
 
-// sendSlave - отправка подзадачи на слейв
-func (mc *ManagerClient) sendSlave(reqBody model.ComputeRequest, node *SlaveNode) error {
-	data, err := json.Marshal(reqBody)
-	if err != nil {
-		return err
+// requeueSubtask - подзадача, которую слейв так и не взял в работу, возвращается в очередь без счета ошибки. Вызывается под mc.mu
+func (mc *ManagerClient) requeueSubtask(uuid string, slaveUuid string) (Subtask, bool) {
+	subtask, ok := mc.subtasksStatus[uuid]
+	if !ok || subtask.status != SUBTASK_SENT || subtask.SlaveNodeUuid != slaveUuid {
+		return subtask, false
 	}
+	mc.releaseSlot(slaveUuid)
+	subtask.status = SUBTASK_WAIT
+	subtask.SlaveNodeUuid = ""
+	subtask.Url = ""
+	mc.subtasksStatus[uuid] = subtask
+	mc.persistSubtask(subtask)
+	mc.notifyWork()
+
+	return subtask, true
+}
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s%s%s", node.Url, node.PublicPort, "/api/v1/addTask"), bytes.NewReader(data))
-	if err != nil {
-		return err
+/*
+leaseSubtask - запись подзадачи как выданной слейву и ее запрос: reqBody со скриптами и данными, refBody только с id блобов.
+Если задачи уже нет, подзадача снимается и ok=false. Вызывается под mc.mu
+*/
+func (mc *ManagerClient) leaseSubtask(subtaskUuid string, taskUuid string, slave *SlaveNode, amount, start uint32) (reqBody, refBody protocol.ComputeRequest, ok bool) {
+	task, ok := mc.taskStatus[taskUuid]
+	if !ok {
+		delete(mc.subtasksStatus, subtaskUuid)
+		mc.forgetSubtask(subtaskUuid)
+		mc.releaseSlot(slave.UUID)
+		log.Printf("[SEND SUBTASK][ERROR] task %s not found, subtask %s dropped\n", taskUuid, subtaskUuid)
+		return reqBody, refBody, false
 	}
 
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+	subtask, ok := mc.subtasksStatus[subtaskUuid]
+	if !ok {
+		subtask = Subtask{
+			uuid:          subtaskUuid,
+			TaskUuid:      taskUuid,
+			SlaveNodeUuid: slave.UUID,
+			Url:           protocol.NodeURL(slave.Node, false),
+			start:         start,
+			amount:        amount,
+			errCount:      0,
+		}
+	} else {
+		subtask.SlaveNodeUuid = slave.UUID
+		subtask.Url = protocol.NodeURL(slave.Node, false)
 	}
-	if resp.StatusCode/100 != 2 {
-		respBody, _ := io.ReadAll(resp.Body)
-		defer resp.Body.Close()
+	subtask.status = SUBTASK_SENT
+	subtask.sendTime = time.Now()
+	mc.subtasksStatus[subtaskUuid] = subtask
+	mc.persistSubtask(subtask)
 
-		return errors.New(string(respBody))
+	reqBody = protocol.ComputeRequest{
+		UuidSubtask: subtaskUuid,
+		Generate:    task.generatorScript,
+		Compute:     task.computeScript,
+		Data:        task.Data,
+		Amount:      amount,
+		Start:       start,
+		Limits:      task.limits,
 	}
+	// в подзадаче передаются только id блобов, слейв скачивает их один раз
+	refBody = reqBody
+	refBody.Generate.Script = ""
+	refBody.Compute.Script = ""
+	refBody.Data = nil
+	refBody.DataRef = task.dataRef
+
+	return reqBody, refBody, true
+}
 
-	return nil
+// sendSlave - отправка подзадачи на слейв
+func (mc *ManagerClient) sendSlave(reqBody protocol.ComputeRequest, node *SlaveNode) error {
+	return mc.slaveClient(node).AddTask(reqBody)
 }
 
-// sendMasterSubTask - отправка решенного куска мастеру для дальнейшего мержа
-func (mc *ManagerClient) sendMasterSubTask(data json.RawMessage, masterUuid string) error {
+// sendMasterSubTask - отправка решенного куска мастеру для дальнейшего мержа, вместе с uuid и диапазоном подзадачи
+func (mc *ManagerClient) sendMasterSubTask(data json.RawMessage, subtask Subtask, masterUuid string) error {
+	taskUuid := subtask.TaskUuid
+	mc.mu.Lock()
 	master, ok := mc.MasterNodes[masterUuid]
+	mc.mu.Unlock()
 	if !ok {
 		return fmt.Errorf("master node not found")
 	}
 
-	reqBody := struct {
-		Data json.RawMessage `json:"Data"`
-	}{data}
-
-	dataReqBody, err := json.Marshal(reqBody)
-
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s%s%s", master.Url, master.PublicPort, "/api/v1/subtask/done"), bytes.NewReader(dataReqBody)) //todo checkme чекнуть как порт будет передаваться {"8080"/":8080"}
-	if err != nil {
-		return err
-	}
-
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode/100 != 2 {
-		respBody, _ := io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-
-		return errors.New(string(respBody))
-	}
-
-	return nil
+	return mc.masterClient(master).SubtaskDone(protocol.SubtaskResult{
+		TaskUUID:    taskUuid,
+		SubtaskUUID: subtask.uuid,
+		Start:       subtask.start,
+		Amount:      subtask.amount,
+		Data:        data,
+	})
 }
 
 /*
@@ -278,150 +429,160 @@ AlertSubtaskError - Уведомление подзадачи об ошибке
 Иначе, подзадача отправляется другому слейву
 */
 func (mc *ManagerClient) AlertSubtaskError(uuid string, slaveUuid string, errorStr string) {
-	if _, ok := mc.SlaveNodes[slaveUuid]; ok {
-		mc.mu.Lock()
-
-		delete(mc.WorkSlaves, slaveUuid)
-		mc.FreeSlaves[slaveUuid] = struct{}{}
-
+	mc.mu.Lock()
+	v, ok := mc.subtasksStatus[uuid]
+	if !ok || v.SlaveNodeUuid != slaveUuid {
+		// подзадача уже решена или передана другому слейву, слот этого слейва за нее уже освобожден
 		mc.mu.Unlock()
-	} else {
-		delete(mc.WorkSlaves, slaveUuid)
 		return
 	}
+	mc.releaseSlot(slaveUuid)
 
-	if v, ok := mc.subtasksStatus[uuid]; ok {
-		v.errCount++
-		if v.errCount > errSubtaskThreshold {
-			mc.alertTaskError(v.TaskUuid, errorStr)
-
-			mc.mu.Lock()
-			delete(mc.subtasksStatus, uuid)
-			mc.mu.Unlock()
-		} else {
-			for newSlaveUuid, _ := range mc.FreeSlaves {
-				mc.mu.Lock()
-
-				slave, ok := mc.SlaveNodes[newSlaveUuid]
-				if !ok {
-					delete(mc.FreeSlaves, newSlaveUuid)
-					mc.mu.Unlock()
-					continue
-				}
-
-				delete(mc.FreeSlaves, newSlaveUuid)
-				mc.WorkSlaves[newSlaveUuid] = struct{}{}
-				mc.mu.Unlock()
+	v.errCount++
+	mc.subtasksStatus[uuid] = v
+	if task, ok := mc.taskStatus[v.TaskUuid]; ok {
+		task.failed++
+		mc.persistProgress(task)
+	}
+	if v.errCount > errSubtaskThreshold {
+		delete(mc.subtasksStatus, uuid)
+		mc.forgetSubtask(uuid)
+		mc.mu.Unlock()
 
-				mc.sendSubTask(v.uuid, v.TaskUuid, slave, v.amount, v.start)
-				break
-			}
+		mc.alertTaskError(v.TaskUuid, errorStr)
+		return
+	}
 
+	// подзадача отдается другому слейву со свободным слотом
+	var slave *SlaveNode
+	for newSlaveUuid := range mc.FreeSlaves {
+		if newSlaveUuid == slaveUuid {
+			continue
+		}
+		s, ok := mc.SlaveNodes[newSlaveUuid]
+		if !ok {
+			delete(mc.FreeSlaves, newSlaveUuid)
+			continue
+		}
+		if s.Pull {
+			continue
 		}
 
+		mc.takeSlot(newSlaveUuid)
+		slave = s
+		break
+	}
+	if slave == nil {
+		// свободных слейвов нет, подзадачу заберет цикл планирования задачи или слейв в режиме pull
+		v.status = SUBTASK_WAIT
+		v.SlaveNodeUuid = ""
+		v.Url = ""
+		mc.subtasksStatus[uuid] = v
+		mc.notifyWork()
+	}
+	mc.persistSubtask(v)
+	mc.mu.Unlock()
+
+	if slave != nil {
+		mc.sendSubTask(v.uuid, v.TaskUuid, slave, v.amount, v.start)
 	}
 
 }
 
-func (mc *ManagerClient) alertTaskError(uuid string, errorStr string) { // отправка уведомления об ошибке мастеру и удаление задачи
-	// найти мастера по uuid , отправить ему ошибку по ручке
-	_, ok := mc.taskStatus[uuid]
+// removeTask - удаление задачи из активных с итоговым статусом и остановка ее цикла планирования. Вызывается под mc.mu
+func (mc *ManagerClient) removeTask(uuid string, status uint8) (*Task, bool) {
+	task, ok := mc.taskStatus[uuid]
 	if !ok {
-		log.Printf("[DONE TASK][ERROR] task not found with uuid %s\n", uuid)
+		return nil, false
 	}
-	mc.mu.Lock()
 	delete(mc.taskStatus, uuid)
-	mc.mu.Unlock()
-
-	master, ok := mc.MasterNodes[uuid]
-	if !ok {
-		log.Printf("[DONE TASK][ERROR] master node not found with uuid %s\n", uuid)
-	} else {
-		master.cancelTask()
+	mc.releaseTaskBlobs(task)
+	task.status = status
+	mc.finishedTasks[uuid] = task
+	if master, ok := mc.MasterNodes[task.MasterUuid]; ok {
+		delete(master.tasks, uuid)
 	}
+	if task.cancelTask != nil {
+		task.cancelTask()
+	}
+	task.finish()
+	mc.persistTask(task) // запись без данных и скриптов, при сжатии журнала они из него уходят
+
+	return task, true
+}
 
-	client := http.Client{}
+/*
+ReportSubtaskError - ошибка подзадачи от слейва с ее типом
 
-	data, err := json.Marshal(errorStr)
-	if err != nil {
-		log.Println(err)
+Превышение лимита шагов или размера результата детерминировано: на другом слейве подзадача упадет так же,
+поэтому вся задача сразу завершается с ошибкой. Подзадача, которую слейв не смог принять, возвращается
+в очередь без счета ошибки. Остальные ошибки идут в AlertSubtaskError на повтор
+*/
+func (mc *ManagerClient) ReportSubtaskError(uuid string, slaveUuid string, errType string, errorStr string) {
+	if errType == protocol.ERROR_REJECTED {
+		mc.mu.Lock()
+		subtask, requeued := mc.requeueSubtask(uuid, slaveUuid)
+		mc.mu.Unlock()
+		if requeued {
+			log.Printf("[SUBTASK ERROR][TASK | %s][SUBTASK | %s][SLAVE | %s] rejected by slave, subtask requeued: %s\n", subtask.TaskUuid, uuid, slaveUuid, errorStr)
+		}
+		return
 	}
 
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s", master.Url, "/api/v1/task/error"), bytes.NewReader(data))
-	if err != nil {
-		log.Println(err)
+	errorStr = fmt.Sprintf("[%s] %s", errType, errorStr)
+
+	if errType != protocol.ERROR_LIMIT_STEPS && errType != protocol.ERROR_LIMIT_RESULT {
+		mc.AlertSubtaskError(uuid, slaveUuid, errorStr)
+		return
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println(err)
+	mc.mu.Lock()
+	v, ok := mc.subtasksStatus[uuid]
+	if !ok || v.SlaveNodeUuid != slaveUuid {
+		mc.mu.Unlock()
+		return
 	}
-	if resp.StatusCode/100 != 2 {
-		respBody, _ := io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		log.Println(err, string(respBody))
+	mc.releaseSlot(slaveUuid)
+	delete(mc.subtasksStatus, uuid)
+	mc.forgetSubtask(uuid)
+	if task, ok := mc.taskStatus[v.TaskUuid]; ok {
+		task.failed++
 	}
+	mc.mu.Unlock()
 
-	log.Printf("[TASK ALERT] ALERT!!!! with uuid: %s\n", uuid)
-
+	log.Printf("[SUBTASK ERROR][TASK | %s][SUBTASK | %s][SLAVE | %s] terminal error: %s\n", v.TaskUuid, uuid, slaveUuid, errorStr)
+	mc.alertTaskError(v.TaskUuid, errorStr)
 }
 
-func (mc *ManagerClient) doneTask(uuid string) {
-	// сделать проверку на то что
-	// Проверяем все имеющиейся подзадачи на uuid Главной таски, если они есть, то дожидаемся от них ответа. и только после этого уведомляем мастера о том что задача выполнилась мастера /task/done
-	_, ok := mc.taskStatus[uuid]
+func (mc *ManagerClient) alertTaskError(uuid string, errorStr string) { // отправка уведомления об ошибке мастеру и удаление задачи
+	// найти мастера задачи, отправить ему ошибку по ручке
+	mc.mu.Lock()
+	task, ok := mc.removeTask(uuid, STATUS_ERROR)
 	if !ok {
-		log.Printf("[DONE TASK][ERROR] task not found with uuid %s\n", uuid)
+		mc.mu.Unlock()
+		log.Printf("[TASK ALERT][ERROR] task not found with uuid %s\n", uuid)
+		return
 	}
-	mc.mu.Lock()
-	delete(mc.taskStatus, uuid)
+	subtasks := mc.takeTaskSubtasks(uuid)
+	master, ok := mc.MasterNodes[task.MasterUuid]
 	mc.mu.Unlock()
 
-	master, ok := mc.MasterNodes[uuid]
+	go mc.cancelSubtasks(subtasks)
 	if !ok {
-		log.Printf("[DONE TASK][ERROR] master node not found with uuid %s\n", uuid)
-	}
-
-	master.cancelTask()
-
-	subtasksMap := make(map[string]Subtask)
-
-	for s, subtask := range mc.subtasksStatus {
-		if subtask.TaskUuid == uuid {
-			subtasksMap[s] = subtask
-		}
-	}
-
-	for len(subtasksMap) != 0 {
-		for s, _ := range subtasksMap {
-			if _, ok := mc.subtasksStatus[s]; !ok {
-				delete(mc.subtasksStatus, s)
-			}
-		}
-		time.Sleep(5 * time.Second)
+		log.Printf("[TASK ALERT][ERROR] master node not found with uuid %s\n", task.MasterUuid)
+		return
 	}
 
-	client := http.Client{}
-
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s%s", master.Url, master.PublicPort, "/api/v1/task/done"), nil)
+	err := mc.masterClient(master).TaskError(uuid, errorStr)
 	if err != nil {
-		log.Println(err)
+		log.Println("[TASK ALERT][ERROR]", err)
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println(err)
-	}
-	if resp.StatusCode/100 != 2 {
-		respBody, _ := io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		log.Println(err, string(respBody))
-	}
+	log.Printf("[TASK ALERT] ALERT!!!! with uuid: %s\n", uuid)
 
-	log.Printf("[TASK DONE] DONE!!!! with uuid: %s\n", uuid)
 }
 
-func (mc *ManagerClient) CompleteSubTask(resp model.CompleteSubtaskRequest) error {
+func (mc *ManagerClient) CompleteSubTask(resp protocol.CompleteSubtaskRequest) error {
 	go func() {
 		err := mc.completeSubTask(resp)
 		if err != nil {
@@ -432,95 +593,178 @@ func (mc *ManagerClient) CompleteSubTask(resp model.CompleteSubtaskRequest) erro
 	return nil
 }
 
-func (mc *ManagerClient) completeSubTask(resp model.CompleteSubtaskRequest) error {
+func (mc *ManagerClient) completeSubTask(resp protocol.CompleteSubtaskRequest) error {
 	mc.mu.Lock()
-	if _, ok := mc.SlaveNodes[resp.SlaveUUID]; ok {
-		delete(mc.WorkSlaves, resp.SlaveUUID)
-		mc.FreeSlaves[resp.SlaveUUID] = struct{}{}
+	subtask, ok := mc.subtasksStatus[resp.SubtaskUUID]
+	if slave, okS := mc.SlaveNodes[resp.SlaveUUID]; okS {
+		if slave.status != SLAVE_DRAINING {
+			slave.status = SLAVE_OK
+		}
+		slave.lastSeen = time.Now()
+		// слот освобождается только у слейва, которому подзадача выдана сейчас: за повтор или
+		// результат после переотправки слот уже был освобожден
+		if ok && subtask.status == SUBTASK_SENT && subtask.SlaveNodeUuid == resp.SlaveUUID {
+			mc.releaseSlot(resp.SlaveUUID)
+		}
+		if resp.FreeSlots != nil {
+			mc.syncFreeSlots(resp.SlaveUUID, *resp.FreeSlots)
+		}
 	}
-	mc.mu.Unlock()
 
-	subtask, ok := mc.subtasksStatus[resp.SubtaskUUID]
-	if !ok {
+	if !ok || subtask.status == SUBTASK_DELIVERING {
+		// повтор результата: подзадачу уже решил другой слейв
+		mc.mu.Unlock()
 		return errors.New("subtask not found")
 	}
-	mc.mu.Lock()
-	delete(mc.subtasksStatus, resp.SubtaskUUID)
-	mc.mu.Unlock()
+	subtask.doneTime = time.Now()
+	if resp.Status != "empty" && subtask.SlaveNodeUuid == resp.SlaveUUID {
+		mc.updateSlavePower(resp.SlaveUUID, subtask.amount, subtask.doneTime.Sub(subtask.sendTime))
+	}
+
+	// подзадача была переотправлена другому слейву, а результат пришел от прежнего: копия больше не нужна
+	var duplicate *Subtask
+	if subtask.status == SUBTASK_SENT && subtask.SlaveNodeUuid != resp.SlaveUUID {
+		mc.releaseSlot(subtask.SlaveNodeUuid)
+		dup := subtask
+		duplicate = &dup
+	}
 
-	mc.mu.Lock()
 	task, ok := mc.taskStatus[subtask.TaskUuid]
+	var dropped []Subtask
+	if !ok || resp.Status == "empty" {
+		delete(mc.subtasksStatus, resp.SubtaskUUID)
+		mc.forgetSubtask(resp.SubtaskUUID)
+		if ok {
+			dropped = mc.exhaustTask(task, subtask.start)
+		}
+	} else {
+		// подзадача удаляется только после того, как мастер принял результат
+		subtask.status = SUBTASK_DELIVERING
+		mc.subtasksStatus[resp.SubtaskUUID] = subtask
+	}
 	mc.mu.Unlock()
 
+	if duplicate != nil {
+		dropped = append(dropped, *duplicate)
+	}
+	if len(dropped) != 0 {
+		go mc.cancelSubtasks(dropped)
+	}
+
 	if !ok {
 		return errors.New("task not found")
 	}
 
 	if resp.Status == "empty" {
-		log.Println("TASK is done with uuid: ", subtask.TaskUuid)
-		go mc.doneTask(subtask.TaskUuid)
+		mc.finishTask(subtask.TaskUuid)
+		return nil
+	}
+
+	err := mc.sendMasterSubTask(resp.Data, subtask, task.MasterUuid)
+
+	mc.mu.Lock()
+	current, ok := mc.subtasksStatus[resp.SubtaskUUID]
+	if !ok {
+		// задачу закрыли, пока результат отправлялся
+		mc.mu.Unlock()
+		return err
+	}
+	// аренда доставки могла истечь, пока результат отправлялся, тогда подзадача уже снова в очереди
+	leased := current.status == SUBTASK_DELIVERING && current.doneTime.Equal(subtask.doneTime)
+	if err != nil && !leased {
+		mc.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		// результат не дошел до мастера, подзадача решается заново. Если мастер его все же принял, он отбросит повтор
+		log.Printf("[COMPLETE_SUBTASK][TASK | %s][SUBTASK | %s] send to master error, subtask requeued: %v\n", subtask.TaskUuid, subtask.uuid, err)
+		subtask.status = SUBTASK_WAIT
+		mc.subtasksStatus[resp.SubtaskUUID] = subtask
+		mc.notifyWork()
+		mc.mu.Unlock()
 		return nil
 	}
+	// мастер принял результат, а подзадача после истечения аренды уже переотправлена: копия больше не нужна
+	var resent *Subtask
+	if !leased && current.status == SUBTASK_SENT {
+		mc.releaseSlot(current.SlaveNodeUuid)
+		resent = &current
+	}
+	delete(mc.subtasksStatus, resp.SubtaskUUID)
+	mc.forgetSubtask(resp.SubtaskUUID)
+	task.completed++
+	task.solved += uint64(subtask.amount)
+	mc.persistProgress(task)
+	mc.mu.Unlock()
+
+	if resent != nil {
+		go mc.cancelSubtasks([]Subtask{*resent})
+	}
+	mc.finishTask(subtask.TaskUuid)
 
-	return mc.sendMasterSubTask(resp.Data, task.MasterUuid)
+	return nil
 }
 
-// subtaskWorker - воркер чекинга состояния решения подзадач у слейвов
+/*
+subtaskWorker - воркер чекинга состояния решения подзадач у слейвов
+
+Каждая отправленная подзадача выдается слейву в аренду на 'SUBTASK_LEASE_TIMEOUT'.
+Если за это время слейв не прислал ни результат, ни ошибку, то слейв помечается подозрительным,
+а подзадача через AlertSubtaskError отправляется другому свободному слейву.
+Подозрительный слейв не получает новых подзадач, пока снова не ответит статусом ожидания задачи.
+Доставка результата мастеру арендуется так же: если она не закончилась за 'SUBTASK_LEASE_TIMEOUT',
+подзадача ставится в очередь заново без счета ошибки
+*/
 func (mc *ManagerClient) subtaskWorker() { // здесь пингуются сабтаски
-	ticker := time.NewTicker(30 * time.Second)
-
-	//client := &http.Client{}
+	ticker := time.NewTicker(mc.cfg.SubtaskCheckInterval)
 
 	for {
 		select {
 		case <-ticker.C:
+			now := time.Now()
+
 			mc.mu.Lock()
-			if len(mc.subtasksStatus) != 0 {
-				mc.mu.Unlock()
+			var expired []Subtask
+			var redeliver int
+			for uuid, subtask := range mc.subtasksStatus {
+				switch {
+				case subtask.status == SUBTASK_SENT && now.Sub(subtask.sendTime) > mc.cfg.SubtaskLeaseTimeout:
+					expired = append(expired, subtask)
+				case subtask.status == SUBTASK_DELIVERING && now.Sub(subtask.doneTime) > mc.cfg.SubtaskLeaseTimeout:
+					log.Printf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s] delivery to master expired, solved at %s\n", subtask.TaskUuid, uuid, subtask.doneTime.Format(time.RFC3339))
+					subtask.status = SUBTASK_WAIT
+					mc.subtasksStatus[uuid] = subtask
+					redeliver++
+				}
+			}
+			if redeliver != 0 {
+				mc.notifyWork()
+			}
+			var suspects []*SlaveNode
+			for _, slave := range mc.SlaveNodes {
+				// слейв в режиме pull снимает подозрение сам, когда снова приходит за подзадачей
+				if slave.status == SLAVE_SUSPECT && !slave.Pull {
+					suspects = append(suspects, slave)
+				}
+			}
+			mc.mu.Unlock()
 
-				//for _, subtask := range mc.subtasksStatus {
-				//
-				//	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s", subtask.Url, "/api/v1/checkStatus"), nil)
-				//	if err != nil {
-				//		log.Printf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s][SLAVE | %s] error: %v\n", subtask.TaskUuid, subtask.uuid, subtask.SlaveNodeUuid, err)
-				//		mc.AlertSubtaskError(subtask.uuid, subtask.SlaveNodeUuid, err.Error())
-				//		continue
-				//	}
-				//
-				//	resp, err := client.Do(req)
-				//	if err != nil {
-				//		log.Printf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s][SLAVE | %s] error: %v\n", subtask.TaskUuid, subtask.uuid, subtask.SlaveNodeUuid, err)
-				//		mc.AlertSubtaskError(subtask.uuid, subtask.SlaveNodeUuid, err.Error())
-				//		continue
-				//	}
-				//	if resp.StatusCode/100 != 2 {
-				//		respBody, _ := io.ReadAll(resp.Body)
-				//		defer resp.Body.Close()
-				//		log.Printf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s][SLAVE | %s] error: %v\n", subtask.TaskUuid, subtask.uuid, subtask.SlaveNodeUuid, errors.New(string(respBody)))
-				//		mc.AlertSubtaskError(subtask.uuid, subtask.SlaveNodeUuid, err.Error())
-				//		continue
-				//	}
-				//
-				//	respBody, _ := io.ReadAll(resp.Body)
-				//	var status = string(respBody)
-				//
-				//	if err != nil {
-				//		log.Printf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s][SLAVE | %s] error: %v\n", subtask.TaskUuid, subtask.uuid, subtask.SlaveNodeUuid, err)
-				//		mc.AlertSubtaskError(subtask.uuid, subtask.SlaveNodeUuid, err.Error())
-				//		continue
-				//	}
-				//	if status == "error" {
-				//		log.Printf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s][SLAVE | %s] slave have 'error' status\n", subtask.TaskUuid, subtask.uuid, subtask.SlaveNodeUuid)
-				//		mc.AlertSubtaskError(subtask.uuid, subtask.SlaveNodeUuid, fmt.Sprintf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s][SLAVE | %s] slave have 'error' status", subtask.TaskUuid, subtask.uuid, subtask.SlaveNodeUuid))
-				//	} else if status != "solving" {
-				//		log.Printf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s][SLAVE | %s] slave dont have 'solving' status\n", subtask.TaskUuid, subtask.uuid, subtask.SlaveNodeUuid)
-				//		mc.AlertSubtaskError(subtask.uuid, subtask.SlaveNodeUuid, fmt.Sprintf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s][SLAVE | %s] slave dont have 'solving' status", subtask.TaskUuid, subtask.uuid, subtask.SlaveNodeUuid))
-				//	}
-				//
-				//}
-
-			} else {
-				mc.mu.Unlock()
+			for _, subtask := range expired {
+				log.Printf("[SUBTASK_WORKER][TASK | %s][SUBTASK | %s][SLAVE | %s] lease expired, sent at %s\n", subtask.TaskUuid, subtask.uuid, subtask.SlaveNodeUuid, subtask.sendTime.Format(time.RFC3339))
+				mc.markSlaveSuspect(subtask.SlaveNodeUuid)
+				mc.AlertSubtaskError(subtask.uuid, subtask.SlaveNodeUuid, "subtask lease expired")
+			}
+
+			for _, slave := range suspects {
+				status, err := mc.checkSlaveStatus(slave)
+				if err != nil {
+					log.Printf("[SUBTASK_WORKER][SLAVE | %s] suspect slave check error: %v\n", slave.UUID, err)
+					continue
+				}
+				if status == SLAVE_STATUS_WAIT_TASK {
+					mc.releaseSlave(slave.UUID)
+					log.Printf("[SUBTASK_WORKER][SLAVE | %s] suspect slave is waiting for tasks again\n", slave.UUID)
+				}
 			}
 
 		}
@@ -529,46 +773,172 @@ func (mc *ManagerClient) subtaskWorker() { // здесь пингуются са
 
 }
 
-func (mc *ManagerClient) SetTask(taskCfg TaskConfig) error {
-	if v, ok := mc.MasterNodes[taskCfg.MasterUUID]; ok {
-		v.generatorScript = taskCfg.GeneratorScript
-		v.computeScript = taskCfg.ComputeScript
-		v.taskName = taskCfg.taskName
-		v.task = Task{
-			MasterUuid: taskCfg.MasterUUID,
-			Data:       taskCfg.Data,
-			counter:    0,
-		}
-		ctx, cancel := context.WithCancel(context.Background())
-		v.cancelTask = cancel
-		mc.MasterNodes[taskCfg.MasterUUID] = v
-		mc.taskStatus[taskCfg.MasterUUID] = Task{
-			MasterUuid: taskCfg.MasterUUID,
-			Data:       taskCfg.Data,
-			counter:    0,
-		}
+// markSlaveSuspect - слейв не вернул подзадачу за время аренды и больше не получает новых подзадач
+func (mc *ManagerClient) markSlaveSuspect(slaveUuid string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 
-		go mc.taskWorker(ctx, taskCfg.MasterUUID)
+	if slave, ok := mc.SlaveNodes[slaveUuid]; ok && slave.status != SLAVE_DRAINING {
+		slave.status = SLAVE_SUSPECT
+		delete(mc.FreeSlaves, slaveUuid)
+	}
+}
+
+// releaseSlave - слейв живой и свободен, снимается подозрение и его слоты возвращаются в пул свободных
+func (mc *ManagerClient) releaseSlave(slaveUuid string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if slave, ok := mc.SlaveNodes[slaveUuid]; ok {
+		slave.status = SLAVE_OK
+		mc.recountSlots(slaveUuid)
+	}
+}
+
+// checkSlaveStatus - запрос статуса слейва (/checkStatus)
+func (mc *ManagerClient) checkSlaveStatus(node *SlaveNode) (string, error) {
+	return mc.slaveClient(node).CheckStatus()
+}
+
+// scriptLimits - ограничения проверки скриптов: лимиты задачи, а если их нет - менеджера
+func (mc *ManagerClient) scriptLimits(l protocol.ExecutionLimits) script.Limits {
+	limits := script.Limits{
+		MaxSteps:       mc.cfg.ScriptMaxSteps,
+		Timeout:        mc.cfg.ScriptTimeout,
+		MaxResultBytes: mc.cfg.ScriptMaxResultBytes,
+	}
+	if l.MaxSteps > 0 {
+		limits.MaxSteps = l.MaxSteps
+	}
+	if l.TimeoutMs > 0 {
+		limits.Timeout = time.Duration(l.TimeoutMs) * time.Millisecond
+	}
+	if l.MaxResultBytes > 0 {
+		limits.MaxResultBytes = l.MaxResultBytes
+	}
+
+	return limits
+}
+
+// SetTask - постановка новой задачи от мастера. Возвращает uuid созданной задачи
+func (mc *ManagerClient) SetTask(taskCfg protocol.TaskConfig) (string, error) {
+	mc.mu.Lock()
+	if _, ok := mc.MasterNodes[taskCfg.MasterUUID]; !ok {
+		mc.mu.Unlock()
+		return "", fmt.Errorf("master node %s not exist", taskCfg.MasterUUID)
+	}
+	err := mc.resolveTaskBlobs(&taskCfg)
+	mc.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	// скрипты проверяются и размер задачи считается один раз, вне mc.mu: скрипт может исполняться долго
+	checked, err := script.Validate(taskCfg.GeneratorScript, taskCfg.ComputeScript, taskCfg.Data, mc.scriptLimits(taskCfg.Limits))
+	if err != nil {
+		log.Printf("[SET TASK][REJECTED] master: %s, %v\n", taskCfg.MasterUUID, err)
+		return "", err
+	}
+	size, sized := checked.Size, checked.Sized
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	master, ok := mc.MasterNodes[taskCfg.MasterUUID]
+	if !ok {
+		return "", fmt.Errorf("master node %s not exist", taskCfg.MasterUUID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	task := &Task{
+		uuid:            uuid2.NewString(),
+		MasterUuid:      taskCfg.MasterUUID,
+		Data:            taskCfg.Data,
+		generatorScript: taskCfg.GeneratorScript,
+		computeScript:   taskCfg.ComputeScript,
+		limits:          taskCfg.Limits,
+		status:          STATUS_WAIT,
+		counter:         0,
+		sized:           sized,
+		size:            size,
+		exhausted:       sized && size == 0,
+		cancelTask:      cancel,
+	}
+	mc.retainTaskBlobs(task)
+	mc.taskStatus[task.uuid] = task
+	master.tasks[task.uuid] = struct{}{}
+	mc.persistTask(task)
+
+	mc.notifyWork()
+
+	go mc.taskWorker(ctx, task.uuid)
+
+	if sized {
+		log.Printf("[SET TASK] master: %s, task: %s, size: %d\n", taskCfg.MasterUUID, task.uuid, size)
 	} else {
-		return fmt.Errorf("master node %s not exist", taskCfg.MasterUUID)
+		log.Printf("[SET TASK] master: %s, task: %s\n", taskCfg.MasterUUID, task.uuid)
 	}
 
-	return nil
+	return task.uuid, nil
 }
 
-func (sd *ManagerClient) RegisterMaster(node model.Node) error {
-	sd.MasterNodes[node.Uuid] = &MasterNode{
-		Node: node,
+func (sd *ManagerClient) RegisterMaster(node protocol.Node) error {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.persistNode(store.NODE_MASTER, node)
+
+	if master, ok := sd.MasterNodes[node.UUID]; ok {
+		master.Node = node
+		master.lastSeen = time.Now()
+		return nil
+	}
+	sd.MasterNodes[node.UUID] = &MasterNode{
+		Node:     node,
+		tasks:    make(map[string]struct{}),
+		lastSeen: time.Now(),
 	}
+	log.Printf("[REGISTER MASTER] %s\n", node.UUID)
 	return nil
 }
 
-func (sd *ManagerClient) RegisterSlave(node model.Node) error {
-	sd.SlaveNodes[node.Uuid] = &SlaveNode{
-		Node: node,
-		//status: "ok",
+/*
+RegisterSlave - регистрация слейва
+
+Повторная регистрация известного слейва обновляет его адрес и слоты на месте: измеренная скорость,
+размер подзадачи, подозрение и накопленные отмены сохраняются
+*/
+func (sd *ManagerClient) RegisterSlave(node protocol.Node) error {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	if slave, ok := sd.SlaveNodes[node.UUID]; ok {
+		slave.Node = node
+		slave.lastSeen = time.Now()
+		if slave.status == SLAVE_DRAINING {
+			// слейв перезапустился после остановки
+			slave.status = SLAVE_OK
+		}
+		if slave.throughput == 0 {
+			// скорость еще не измерена, размер подзадачи считается от нового бенчмарка
+			slave.power = sd.initialSlavePower(node)
+		}
+		sd.recountSlots(node.UUID)
+		log.Printf("[REGISTER SLAVE] %s again, slots: %d, status: %s, power: %d\n", node.UUID, slave.slots(), slave.status, slave.power)
+		sd.persistNode(store.NODE_SLAVE, node)
+		return nil
+	}
+
+	slave := &SlaveNode{
+		Node:     node,
+		status:   SLAVE_OK,
+		lastSeen: time.Now(),
 	}
-	sd.FreeSlaves[node.Uuid] = struct{}{}
+	slave.power = sd.initialSlavePower(node)
+	sd.SlaveNodes[node.UUID] = slave
+	sd.recountSlots(node.UUID)
+	log.Printf("[REGISTER SLAVE] %s cpu: %d, slots: %d, benchmark: %.2f, power: %d\n", node.UUID, node.CPU, slave.slots(), node.Benchmark, slave.power)
+	sd.persistNode(store.NODE_SLAVE, node)
 	return nil
 }
 
@@ -578,12 +948,19 @@ func (sd *ManagerClient) checkMasterHealthWorker() {
 		select {
 		case <-ticker.C:
 			var res, ex int
+			sd.mu.Lock()
+			masters := make(map[string]*MasterNode, len(sd.MasterNodes))
 			for uuid, node := range sd.MasterNodes {
-				_, err := http.Get(fmt.Sprintf("http://%s%s/health", node.Url, node.PrivatePort))
+				masters[uuid] = node
+			}
+			sd.mu.Unlock()
+
+			for uuid, node := range masters {
+				err := client.Health(protocol.NodeURL(node.Node, true), sd.cfg.NodeTimeout)
 				if err != nil {
 					ex++
 					sd.mu.Lock()
-					delete(sd.MasterNodes, uuid)
+					sd.dropMaster(uuid)
 					sd.mu.Unlock()
 					log.Println("service disconnected:", node)
 				} else {
@@ -602,14 +979,23 @@ func (sd *ManagerClient) checkSlaveHealthWorker() {
 		select {
 		case <-ticker.C:
 			var res, ex int
+			sd.mu.Lock()
+			slaves := make(map[string]*SlaveNode, len(sd.SlaveNodes))
 			for uuid, node := range sd.SlaveNodes {
-				_, err := http.Get(fmt.Sprintf("http://%s%s/health", node.Url, node.PrivatePort))
+				slaves[uuid] = node
+			}
+			sd.mu.Unlock()
+
+			for uuid, node := range slaves {
+				if node.Pull {
+					// слейв в режиме pull может быть недоступен менеджеру (NAT), он живой, пока шлет heartbeat и опросы
+					continue
+				}
+				err := client.Health(protocol.NodeURL(node.Node, true), sd.cfg.NodeTimeout)
 				if err != nil {
 					ex++
 					sd.mu.Lock()
-					delete(sd.SlaveNodes, uuid)
-					delete(sd.FreeSlaves, uuid)
-					delete(sd.WorkSlaves, uuid)
+					sd.dropSlave(uuid)
 					sd.mu.Unlock()
 					log.Println("service disconnected:", node)
 				} else {
@@ -622,12 +1008,18 @@ func (sd *ManagerClient) checkSlaveHealthWorker() {
 	}
 }
 
+// CloseTask - закрытие задачи по ее uuid
 func (mc *ManagerClient) CloseTask(uuid string) error {
-	if master, ok := mc.MasterNodes[uuid]; ok {
-		master.cancelTask()
-	} else {
-		delete(mc.taskStatus, uuid)
-		return fmt.Errorf("master node %s not exist", uuid)
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	task, ok := mc.removeTask(uuid, STATUS_DONE)
+	if !ok {
+		return fmt.Errorf("task %s not exist", uuid)
 	}
+	go mc.cancelSubtasks(mc.takeTaskSubtasks(uuid))
+
+	log.Printf("[CLOSE TASK] task %s closed by master %s\n", uuid, task.MasterUuid)
+
 	return nil
 }
@@ -0,0 +1,138 @@
+package manager_client
+
+import (
+	"context"
+	"log"
+	"protocol"
+	"time"
+)
+
+// pullRecheckInterval - как часто длинный опрос перепроверяет задачи, даже если его не разбудили
+const pullRecheckInterval = time.Second
+
+/*
+NextSubtask - ответ слейву в режиме pull (GET /subtask/next): подзадача и отмены его подзадач
+
+Если у слейва есть свободный воркер (free) и работа, подзадача сразу выдается ему в аренду. Иначе запрос ждет,
+пока появится работа, отмена для слейва или, если слейв занят, освободится его слот, либо не отменится ctx
+(тогда ответ пустой). Опрос считается сигналом жизни слейва, а подозрение с него снимается: слейв пришел
+за работой, значит у него есть свободный воркер
+*/
+func (mc *ManagerClient) NextSubtask(ctx context.Context, slaveUuid string, free bool) (resp protocol.NextSubtaskResp, err error) {
+	ticker := time.NewTicker(pullRecheckInterval)
+	defer ticker.Stop()
+
+	for {
+		mc.mu.Lock()
+		slave, ok := mc.SlaveNodes[slaveUuid]
+		if !ok {
+			mc.mu.Unlock()
+			return resp, protocol.ErrUnknownNode
+		}
+		slave.lastSeen = time.Now()
+		resp.Cancel = append(resp.Cancel, slave.cancels...)
+		slave.cancels = nil
+
+		// подзадача выдается, только пока слейв еще ждет ответа
+		if free && ctx.Err() == nil {
+			a, taskUuid, found, err := mc.pullAssign(slave)
+			if err != nil && len(resp.Cancel) == 0 {
+				mc.mu.Unlock()
+				return resp, err
+			}
+			if found {
+				_, req, ok := mc.leaseSubtask(a.subtaskUuid, taskUuid, a.slave, a.amount, a.start)
+				if ok {
+					mc.mu.Unlock()
+					log.Printf("[PULL SUBTASK][TASK | %s][SUBTASK | %s][SLAVE | %s] start: %d, amount: %d\n", taskUuid, a.subtaskUuid, slaveUuid, a.start, a.amount)
+					resp.Subtask = &req
+					return resp, nil
+				}
+				mc.mu.Unlock()
+				continue
+			}
+		} else if mc.FreeSlaves[slaveUuid] > 0 {
+			// слот слейва освободился, пусть спросит работу
+			mc.mu.Unlock()
+			return resp, nil
+		}
+		if len(resp.Cancel) != 0 {
+			mc.mu.Unlock()
+			return resp, nil
+		}
+		wake := mc.workReady
+		mc.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return resp, nil
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// pullAssign - подзадача любой активной задачи для слейва, если у него есть свободный слот. Вызывается под mc.mu
+func (mc *ManagerClient) pullAssign(slave *SlaveNode) (subtaskAssign, string, bool, error) {
+	switch slave.status {
+	case SLAVE_DRAINING:
+		return subtaskAssign{}, "", false, protocol.ErrDraining
+	case SLAVE_SUSPECT:
+		slave.status = SLAVE_OK
+		mc.recountSlots(slave.UUID)
+		log.Printf("[PULL SUBTASK][SLAVE | %s] suspect slave is waiting for tasks again\n", slave.UUID)
+	}
+	if mc.FreeSlaves[slave.UUID] <= 0 {
+		return subtaskAssign{}, "", false, nil
+	}
+
+	for taskUuid, task := range mc.taskStatus {
+		var waiting []Subtask
+		for _, subtask := range mc.subtasksStatus {
+			if subtask.TaskUuid == taskUuid && subtask.status == SUBTASK_WAIT {
+				waiting = append(waiting, subtask)
+				break
+			}
+		}
+		if task.exhausted && len(waiting) == 0 {
+			continue
+		}
+
+		a, _ := mc.assignNext(task, waiting, slave)
+		task.status = STATUS_SOLVING
+		mc.persistProgress(task)
+
+		return a, taskUuid, true, nil
+	}
+
+	return subtaskAssign{}, "", false, nil
+}
+
+/*
+ReturnNext - ответ на длинный опрос не дошел до слейва
+
+Выданная подзадача сразу возвращается в очередь, без ошибки и без ожидания конца аренды,
+а отмены ждут следующего опроса слейва
+*/
+func (mc *ManagerClient) ReturnNext(slaveUuid string, resp protocol.NextSubtaskResp) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if slave, ok := mc.SlaveNodes[slaveUuid]; ok {
+		slave.cancels = append(resp.Cancel, slave.cancels...)
+	}
+	if resp.Subtask == nil {
+		return
+	}
+	subtask, ok := mc.requeueSubtask(resp.Subtask.UuidSubtask, slaveUuid)
+	if !ok {
+		return
+	}
+	log.Printf("[PULL SUBTASK][TASK | %s][SUBTASK | %s][SLAVE | %s] slave is gone, subtask requeued\n", subtask.TaskUuid, subtask.uuid, slaveUuid)
+}
+
+// notifyWork - будит длинные опросы слейвов: появилась задача, подзадача ждет повторной отправки или отмена для слейва. Вызывается под mc.mu
+func (mc *ManagerClient) notifyWork() {
+	close(mc.workReady)
+	mc.workReady = make(chan struct{})
+}
@@ -0,0 +1,181 @@
+package manager_client
+
+import (
+	"context"
+	"log"
+	"manager-node/internal/store"
+	"protocol"
+	"time"
+)
+
+// Запись состояния в хранилище. Все методы вызываются под mc.mu, ошибки хранилища только логируются
+
+func (mc *ManagerClient) persistNode(kind string, node protocol.Node) {
+	if err := mc.store.SaveNode(store.NodeRecord{Kind: kind, Node: node}); err != nil {
+		log.Println("[STORE][ERROR] save node:", err)
+	}
+}
+
+func (mc *ManagerClient) forgetNode(uuid string) {
+	if err := mc.store.DeleteNode(uuid); err != nil {
+		log.Println("[STORE][ERROR] delete node:", err)
+	}
+}
+
+func (mc *ManagerClient) persistTask(task *Task) {
+	err := mc.store.SaveTask(store.TaskRecord{
+		UUID:            task.uuid,
+		MasterUUID:      task.MasterUuid,
+		TaskName:        task.taskName,
+		GeneratorScript: task.generatorScript,
+		ComputeScript:   task.computeScript,
+		Data:            task.Data,
+		Limits:          task.limits,
+		TaskProgress:    task.progress(),
+	})
+	if err != nil {
+		log.Println("[STORE][ERROR] save task:", err)
+	}
+}
+
+func (mc *ManagerClient) persistProgress(task *Task) {
+	if err := mc.store.SaveTaskProgress(task.progress()); err != nil {
+		log.Println("[STORE][ERROR] save task progress:", err)
+	}
+}
+
+func (mc *ManagerClient) forgetTask(uuid string) {
+	if err := mc.store.DeleteTask(uuid); err != nil {
+		log.Println("[STORE][ERROR] delete task:", err)
+	}
+}
+
+func (mc *ManagerClient) persistSubtask(subtask Subtask) {
+	err := mc.store.SaveSubtask(store.SubtaskRecord{
+		UUID:      subtask.uuid,
+		TaskUUID:  subtask.TaskUuid,
+		SlaveUUID: subtask.SlaveNodeUuid,
+		Start:     subtask.start,
+		Amount:    subtask.amount,
+		ErrCount:  subtask.errCount,
+	})
+	if err != nil {
+		log.Println("[STORE][ERROR] save subtask:", err)
+	}
+}
+
+func (mc *ManagerClient) forgetSubtask(uuid string) {
+	if err := mc.store.DeleteSubtask(uuid); err != nil {
+		log.Println("[STORE][ERROR] delete subtask:", err)
+	}
+}
+
+func (t *Task) progress() store.TaskProgress {
+	return store.TaskProgress{
+		UUID:      t.uuid,
+		Status:    t.status,
+		Counter:   t.counter,
+		Completed: t.completed,
+		Failed:    t.failed,
+		Exhausted: t.exhausted,
+		End:       t.end,
+		Dropped:   t.dropped,
+		Sized:     t.sized,
+		Size:      t.size,
+		Solved:    t.solved,
+	}
+}
+
+/*
+restore - восстановление состояния из хранилища при старте менеджера
+
+Незавершенные задачи запускаются заново со своими циклами планирования,
+а подзадачи, которые были в работе, ставятся в очередь на повторную отправку
+*/
+func (mc *ManagerClient) restore() error {
+	state, err := mc.store.Load()
+	if err != nil {
+		return err
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	for uuid, rec := range state.Nodes {
+		switch rec.Kind {
+		case store.NODE_MASTER:
+			// после рестарта у ноды есть 'HEARTBEAT_GRACE', чтобы прислать heartbeat
+			mc.MasterNodes[uuid] = &MasterNode{Node: rec.Node, tasks: make(map[string]struct{}), lastSeen: time.Now()}
+		case store.NODE_SLAVE:
+			mc.SlaveNodes[uuid] = &SlaveNode{Node: rec.Node, status: SLAVE_OK, lastSeen: time.Now()}
+			mc.SlaveNodes[uuid].power = mc.initialSlavePower(rec.Node)
+			mc.FreeSlaves[uuid] = mc.SlaveNodes[uuid].slots()
+		}
+	}
+
+	var active []string
+	for uuid, rec := range state.Tasks {
+		task := &Task{
+			uuid:            uuid,
+			MasterUuid:      rec.MasterUUID,
+			Data:            rec.Data,
+			generatorScript: rec.GeneratorScript,
+			computeScript:   rec.ComputeScript,
+			taskName:        rec.TaskName,
+			limits:          rec.Limits,
+			status:          rec.Status,
+			counter:         rec.Counter,
+			completed:       rec.Completed,
+			failed:          rec.Failed,
+			exhausted:       rec.Exhausted,
+			end:             rec.End,
+			dropped:         rec.Dropped,
+			sized:           rec.Sized,
+			size:            rec.Size,
+			solved:          rec.Solved,
+		}
+		if task.status == STATUS_DONE || task.status == STATUS_ERROR {
+			// время завершения не хранится, 'FINISHED_TASK_TTL' отсчитывается заново от рестарта
+			task.finish()
+			mc.finishedTasks[uuid] = task
+			if len(rec.Data) != 0 || rec.GeneratorScript.Script != "" || rec.ComputeScript.Script != "" {
+				mc.persistTask(task) // журнал старой версии хранил данные и скрипты завершенных задач
+			}
+			continue
+		}
+		mc.retainTaskBlobs(task)
+		mc.taskStatus[uuid] = task
+		if master, ok := mc.MasterNodes[task.MasterUuid]; ok {
+			master.tasks[uuid] = struct{}{}
+		}
+		active = append(active, uuid)
+	}
+
+	var requeued int
+	for uuid, rec := range state.Subtasks {
+		if _, ok := mc.taskStatus[rec.TaskUUID]; !ok {
+			mc.forgetSubtask(uuid)
+			continue
+		}
+		mc.subtasksStatus[uuid] = Subtask{
+			uuid:     uuid,
+			TaskUuid: rec.TaskUUID,
+			start:    rec.Start,
+			amount:   rec.Amount,
+			errCount: rec.ErrCount,
+			status:   SUBTASK_WAIT,
+		}
+		requeued++
+	}
+
+	for _, uuid := range active {
+		ctx, cancel := context.WithCancel(context.Background())
+		mc.taskStatus[uuid].cancelTask = cancel
+		go mc.taskWorker(ctx, uuid)
+	}
+
+	log.Printf("[STORE] restored masters: %d, slaves: %d, active tasks: %d, subtasks to re-dispatch: %d\n",
+		len(mc.MasterNodes), len(mc.SlaveNodes), len(active), requeued)
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package manager_client
+
+import (
+	"log"
+	"math"
+	"protocol"
+	"time"
+)
+
+const (
+	minSlavePower = 1
+	maxSlavePower = 100000
+
+	throughputSmoothing = 0.5 // вес нового замера в скользящей средней скорости слейва
+	maxPowerStep        = 4   // во сколько раз максимум меняется размер подзадачи за один замер
+)
+
+/*
+initialSlavePower - размер подзадачи для только что зарегистрированного слейва
+
+Пока по слейву нет замеров, размер берется из его бенчмарка относительно среднего бенчмарка
+остальных слейвов: слейв вдвое быстрее среднего получает вдвое больше элементов. Вызывается под mc.mu
+*/
+func (mc *ManagerClient) initialSlavePower(node protocol.Node) uint32 {
+	if node.Benchmark <= 0 {
+		return defaultSlavePower
+	}
+
+	sum, n := node.Benchmark, 1
+	for uuid, slave := range mc.SlaveNodes {
+		if uuid == node.UUID || slave.Benchmark <= 0 {
+			continue
+		}
+		sum += slave.Benchmark
+		n++
+	}
+	mean := sum / float64(n)
+
+	return clampPower(defaultSlavePower * node.Benchmark / mean)
+}
+
+/*
+updateSlavePower - подстройка размера подзадачи слейва под его измеренную скорость
+
+Скорость (элементов/сек) считается по времени от отправки до получения результата и сглаживается,
+а новый размер выбирается так, чтобы подзадача решалась примерно 'SUBTASK_TARGET_DURATION'.
+Так быстрые и медленные слейвы тратят на свои куски примерно одинаковое время. Вызывается под mc.mu
+*/
+func (mc *ManagerClient) updateSlavePower(slaveUuid string, amount uint32, elapsed time.Duration) {
+	slave, ok := mc.SlaveNodes[slaveUuid]
+	if !ok || amount == 0 || elapsed <= 0 {
+		return
+	}
+
+	measured := float64(amount) / elapsed.Seconds()
+	if slave.throughput == 0 {
+		slave.throughput = measured
+	} else {
+		slave.throughput = throughputSmoothing*measured + (1-throughputSmoothing)*slave.throughput
+	}
+
+	target := slave.throughput * mc.cfg.SubtaskTargetDuration.Seconds()
+	if current := float64(slave.power); current > 0 {
+		target = math.Max(current/maxPowerStep, math.Min(current*maxPowerStep, target))
+	}
+	power := clampPower(target)
+	if power != slave.power {
+		log.Printf("[SLAVE POWER][%s] %d items in %s, throughput: %.2f/s, power: %d -> %d\n", slaveUuid, amount, elapsed, slave.throughput, slave.power, power)
+		slave.power = power
+	}
+}
+
+func clampPower(v float64) uint32 {
+	v = math.Round(v)
+	if v < minSlavePower {
+		return minSlavePower
+	}
+	if v > maxSlavePower {
+		return maxSlavePower
+	}
+	return uint32(v)
+}
@@ -0,0 +1,87 @@
+package manager_client
+
+import (
+	"errors"
+	"log"
+)
+
+var ErrNodeNotFound = errors.New("node not found")
+
+/*
+RemoveNode - снятие ноды с учета (DELETE /node/remove)
+
+Нода сама снимается при остановке, не дожидаясь проверки жизни. При drain слейв только перестает
+получать новые подзадачи, а отправленные ему он дорешивает и снимается вторым запросом
+*/
+func (mc *ManagerClient) RemoveNode(uuid string, drain bool) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if _, ok := mc.MasterNodes[uuid]; ok {
+		mc.dropMaster(uuid)
+		log.Printf("[REMOVE NODE] master %s removed\n", uuid)
+		return nil
+	}
+
+	slave, ok := mc.SlaveNodes[uuid]
+	if !ok {
+		return ErrNodeNotFound
+	}
+	if drain {
+		slave.status = SLAVE_DRAINING
+		delete(mc.FreeSlaves, uuid)
+		log.Printf("[REMOVE NODE] slave %s is draining, subtasks in work: %d\n", uuid, mc.WorkSlaves[uuid])
+		return nil
+	}
+
+	requeued := mc.dropSlave(uuid)
+	log.Printf("[REMOVE NODE] slave %s removed, subtasks requeued: %d\n", uuid, requeued)
+
+	return nil
+}
+
+// dropMaster - удаление мастера вместе с его задачами. Вызывается под mc.mu
+func (mc *ManagerClient) dropMaster(uuid string) {
+	master, ok := mc.MasterNodes[uuid]
+	if !ok {
+		return
+	}
+	for taskUuid := range master.tasks {
+		mc.removeTask(taskUuid, STATUS_ERROR)
+		go mc.cancelSubtasks(mc.takeTaskSubtasks(taskUuid))
+	}
+	master.closeGrpc()
+	delete(mc.MasterNodes, uuid)
+	mc.forgetNode(uuid)
+}
+
+/*
+dropSlave - удаление слейва. Вызывается под mc.mu
+
+Отправленные ему подзадачи сразу ставятся в очередь на повторную отправку, не дожидаясь конца аренды.
+Если слейв все же пришлет результат, он будет принят, а повторная отправка отменена
+*/
+func (mc *ManagerClient) dropSlave(uuid string) int {
+	delete(mc.SlaveNodes, uuid)
+	delete(mc.FreeSlaves, uuid)
+	delete(mc.WorkSlaves, uuid)
+	mc.forgetNode(uuid)
+
+	requeued := 0
+	for subtaskUuid, subtask := range mc.subtasksStatus {
+		if subtask.status != SUBTASK_SENT || subtask.SlaveNodeUuid != uuid {
+			continue
+		}
+		subtask.status = SUBTASK_WAIT
+		subtask.SlaveNodeUuid = ""
+		subtask.Url = ""
+		mc.subtasksStatus[subtaskUuid] = subtask
+		mc.persistSubtask(subtask)
+		requeued++
+	}
+	if requeued != 0 {
+		mc.notifyWork()
+	}
+
+	return requeued
+}
@@ -0,0 +1,84 @@
+package manager_client
+
+// Учет слотов слейвов. Слейв решает несколько подзадач параллельно (по одной на воркер),
+// поэтому FreeSlaves хранит кол-во свободных слотов, а WorkSlaves - кол-во занятых.
+// Все методы вызываются под mc.mu
+
+// slots - кол-во параллельных воркеров слейва
+func (s *SlaveNode) slots() int {
+	if s.Slots > 0 {
+		return s.Slots
+	}
+	return 1
+}
+
+// takeSlot - занять свободный слот слейва под подзадачу
+func (mc *ManagerClient) takeSlot(uuid string) {
+	if mc.FreeSlaves[uuid] <= 1 {
+		delete(mc.FreeSlaves, uuid)
+	} else {
+		mc.FreeSlaves[uuid]--
+	}
+	mc.WorkSlaves[uuid]++
+}
+
+// releaseSlot - вернуть слот слейва после результата или ошибки подзадачи
+func (mc *ManagerClient) releaseSlot(uuid string) {
+	if mc.WorkSlaves[uuid] <= 1 {
+		delete(mc.WorkSlaves, uuid)
+	} else {
+		mc.WorkSlaves[uuid]--
+	}
+
+	slave, ok := mc.SlaveNodes[uuid]
+	if !ok || slave.status != SLAVE_OK {
+		return
+	}
+	if free := slave.slots() - mc.WorkSlaves[uuid]; free > 0 {
+		mc.FreeSlaves[uuid] = free
+		if slave.Pull {
+			mc.notifyWork()
+		}
+	}
+}
+
+// syncFreeSlots - поправка свободных слотов по данным самого слейва (например, он еще решает подзадачи, выданные до рестарта менеджера)
+func (mc *ManagerClient) syncFreeSlots(uuid string, reported int) {
+	free, ok := mc.FreeSlaves[uuid]
+	if !ok || free <= reported {
+		return
+	}
+	if reported <= 0 {
+		delete(mc.FreeSlaves, uuid)
+	} else {
+		mc.FreeSlaves[uuid] = reported
+	}
+}
+
+// recountSlots - пересчет слотов слейва по отправленным ему подзадачам
+func (mc *ManagerClient) recountSlots(uuid string) {
+	slave, ok := mc.SlaveNodes[uuid]
+	if !ok {
+		delete(mc.FreeSlaves, uuid)
+		delete(mc.WorkSlaves, uuid)
+		return
+	}
+
+	busy := 0
+	for _, subtask := range mc.subtasksStatus {
+		if subtask.status == SUBTASK_SENT && subtask.SlaveNodeUuid == uuid {
+			busy++
+		}
+	}
+
+	if busy > 0 {
+		mc.WorkSlaves[uuid] = busy
+	} else {
+		delete(mc.WorkSlaves, uuid)
+	}
+	if free := slave.slots() - busy; free > 0 && slave.status == SLAVE_OK {
+		mc.FreeSlaves[uuid] = free
+	} else {
+		delete(mc.FreeSlaves, uuid)
+	}
+}
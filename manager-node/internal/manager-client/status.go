@@ -0,0 +1,126 @@
+package manager_client
+
+import (
+	"fmt"
+	"manager-node/internal/store"
+	"math"
+	"protocol"
+	"sort"
+)
+
+// GetTaskStatus - текущее состояние задачи по ее uuid
+func (mc *ManagerClient) GetTaskStatus(uuid string) (protocol.TaskStatus, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	task, ok := mc.taskStatus[uuid]
+	if !ok {
+		task, ok = mc.finishedTasks[uuid]
+	}
+	if !ok {
+		return protocol.TaskStatus{}, fmt.Errorf("task %s not exist", uuid)
+	}
+
+	return mc.taskStatusInfo(task), nil
+}
+
+// ListTasks - состояние всех задач менеджера, активных и завершенных
+func (mc *ManagerClient) ListTasks() []protocol.TaskStatus {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	infos := make([]protocol.TaskStatus, 0, len(mc.taskStatus)+len(mc.finishedTasks))
+	for _, task := range mc.taskStatus {
+		infos = append(infos, mc.taskStatusInfo(task))
+	}
+	for _, task := range mc.finishedTasks {
+		infos = append(infos, mc.taskStatusInfo(task))
+	}
+	sort.Slice(infos, func(i, k int) bool { return infos[i].TaskUUID < infos[k].TaskUUID })
+
+	return infos
+}
+
+// taskStatusInfo - вызывается под mc.mu
+func (mc *ManagerClient) taskStatusInfo(task *Task) protocol.TaskStatus {
+	uuid := task.uuid
+	info := protocol.TaskStatus{
+		TaskUUID:   uuid,
+		MasterUUID: task.MasterUuid,
+		Status:     statusStr[task.status],
+		Counter:    task.counter,
+		Completed:  task.completed,
+		Failed:     task.failed,
+		Exhausted:  task.exhausted,
+		End:        task.end,
+		Slaves:     []string{},
+	}
+	if task.sized {
+		size, progress := task.size, 100.0
+		if size > 0 {
+			progress = math.Min(100, float64(task.solved)*100/float64(size))
+		}
+		info.Size, info.Progress = &size, &progress
+	}
+
+	slaves := make(map[string]struct{})
+	for _, subtask := range mc.subtasksStatus {
+		if subtask.TaskUuid != uuid {
+			continue
+		}
+		info.InFlight++
+		if subtask.SlaveNodeUuid != "" {
+			slaves[subtask.SlaveNodeUuid] = struct{}{}
+		}
+	}
+	for slaveUuid := range slaves {
+		info.Slaves = append(info.Slaves, slaveUuid)
+	}
+	sort.Strings(info.Slaves)
+
+	return info
+}
+
+// ListNodes - зарегистрированные мастера и слейвы
+func (mc *ManagerClient) ListNodes() []protocol.NodeInfo {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	infos := make([]protocol.NodeInfo, 0, len(mc.MasterNodes)+len(mc.SlaveNodes))
+	for uuid, master := range mc.MasterNodes {
+		infos = append(infos, protocol.NodeInfo{
+			Kind:        store.NODE_MASTER,
+			UUID:        uuid,
+			Url:         master.Url,
+			PublicPort:  master.PublicPort,
+			ActiveTasks: len(master.tasks),
+			Load:        master.heartbeat.Load,
+			Version:     master.heartbeat.Version,
+			LastSeen:    master.lastSeen,
+		})
+	}
+	for uuid, slave := range mc.SlaveNodes {
+		infos = append(infos, protocol.NodeInfo{
+			Kind:       store.NODE_SLAVE,
+			UUID:       uuid,
+			Url:        slave.Url,
+			PublicPort: slave.PublicPort,
+			Status:     slave.status,
+			Slots:      slave.slots(),
+			FreeSlots:  mc.FreeSlaves[uuid],
+			Power:      slave.power,
+			Benchmark:  slave.Benchmark,
+			Load:       slave.heartbeat.Load,
+			Version:    slave.heartbeat.Version,
+			LastSeen:   slave.lastSeen,
+		})
+	}
+	sort.Slice(infos, func(i, k int) bool {
+		if infos[i].Kind != infos[k].Kind {
+			return infos[i].Kind < infos[k].Kind
+		}
+		return infos[i].UUID < infos[k].UUID
+	})
+
+	return infos
+}
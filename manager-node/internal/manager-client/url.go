@@ -0,0 +1,81 @@
+package manager_client
+
+import (
+	"log"
+	"net"
+	"protocol"
+	"protocol/client"
+	"strings"
+)
+
+// slaveClient - клиент API слейва: поток Dispatch, если слейв подключен по gRPC, иначе REST
+func (mc *ManagerClient) slaveClient(node *SlaveNode) client.SlaveAPI {
+	mc.mu.Lock()
+	conn, ok := mc.streams[node.UUID]
+	mc.mu.Unlock()
+	if ok {
+		return conn
+	}
+
+	return client.NewSlave(protocol.NodeURL(node.Node, false), mc.cfg.NodeTimeout)
+}
+
+/*
+masterClient - клиент API мастера: gRPC, если мастер зарегистрировался с GrpcPort, иначе REST
+
+Соединение создается при первом обращении и пересоздается, если мастер перерегистрировался с другим адресом
+*/
+func (mc *ManagerClient) masterClient(node *MasterNode) client.MasterAPI {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if node.GrpcPort == "" {
+		return client.NewMaster(protocol.NodeURL(node.Node, false), mc.cfg.NodeTimeout)
+	}
+
+	addr := protocol.HostPort(node.Url, node.GrpcPort)
+	if node.grpc != nil && node.grpcAddr == addr {
+		return node.grpc
+	}
+	node.closeGrpc()
+
+	conn, err := client.DialMaster(addr, mc.cfg.NodeTimeout)
+	if err != nil {
+		log.Printf("[MASTER][ERROR] grpc %s: %v, fallback to REST\n", addr, err)
+		return client.NewMaster(protocol.NodeURL(node.Node, false), mc.cfg.NodeTimeout)
+	}
+	node.grpc, node.grpcAddr = conn, addr
+
+	return conn
+}
+
+/*
+ResolveNodeAddr - адрес ноды для регистрации
+
+Нода сообщает свой адрес сама (ADVERTISE_ADDR или адрес исходящего интерфейса). Если адрес не указан,
+неконкретный (0.0.0.0, ::) или loopback, а запрос пришел не с loopback - берется адрес, с которого
+пришел запрос регистрации: по такому адресу менеджер до ноды не достучится
+*/
+func ResolveNodeAddr(advertised string, remote net.IP) string {
+	if remote == nil || remote.IsUnspecified() {
+		return advertised
+	}
+	if advertised == "" {
+		return remote.String()
+	}
+
+	host := strings.Trim(advertised, "[]")
+	if host == "localhost" {
+		host = "127.0.0.1"
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		// имя хоста - ноде виднее
+		return advertised
+	}
+	if ip.IsUnspecified() || (ip.IsLoopback() && !remote.IsLoopback()) {
+		return remote.String()
+	}
+
+	return advertised
+}
@@ -0,0 +1,288 @@
+package script
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+	"log"
+	"math"
+	"protocol"
+	"protocol/starlarkdata"
+	"time"
+)
+
+// SIZE_FUNC_NAME - необязательная функция скрипта генерации, которая возвращает кол-во элементов задачи
+const SIZE_FUNC_NAME = "size"
+
+// скрипты и этапы проверки в protocol.ScriptError
+const (
+	SCRIPT_GENERATOR = "generator"
+	SCRIPT_COMPUTE   = "compute"
+	SCRIPT_INPUT     = "input"
+
+	STAGE_PARSE     = "parse"
+	STAGE_SYNTAX    = "syntax"
+	STAGE_RESOLVE   = "resolve"
+	STAGE_EXEC      = "exec"
+	STAGE_SIGNATURE = "signature"
+	STAGE_DRY_RUN   = "dry_run"
+	STAGE_SIZE      = "size"
+)
+
+// имена, которые слейв подставляет в окружение скриптов
+var predeclaredNames = map[string]struct{}{"input_data": {}, "amount": {}, "start": {}, "error": {}}
+
+// Limits - ограничения на исполнение скриптов при проверке, 0 - без ограничения
+type Limits struct {
+	MaxSteps       uint64        // шагов на каждый из скриптов
+	Timeout        time.Duration // на всю проверку, по таймеру поток скрипта отменяется
+	MaxResultBytes int           // размер JSON данных пробного вызова генератора
+}
+
+// Result - то, что менеджер узнал о задаче при проверке
+type Result struct {
+	Sized bool   // в скрипте генерации есть size(input_data)
+	Size  uint32 // размер задачи, если Sized
+}
+
+/*
+Validate - проверка скриптов задачи перед раздачей слейвам
+
+Оба скрипта разбираются и резолвятся так же, как на слейве, модули исполняются, у функций
+проверяется кол-во параметров: generate(input_data, amount, start) и compute(input_data).
+Генератор пробно вызывается на диапазоне из одного элемента, а если в нем есть size(input_data) -
+считается размер задачи. Ошибки возвращаются как *protocol.ScriptError с позицией в скрипте.
+Скрипты недоверенные и исполняются в процессе менеджера, поэтому проверка ограничена по шагам, времени
+и размеру данных пробного вызова
+*/
+func Validate(generator, compute protocol.ScriptConfig, data json.RawMessage, limits Limits) (Result, error) {
+	var res Result
+
+	input, err := starlarkdata.ParseInput(data)
+	if err != nil {
+		return res, &protocol.ScriptError{Script: SCRIPT_INPUT, Stage: STAGE_PARSE, Msg: err.Error()}
+	}
+
+	var deadline time.Time
+	if limits.Timeout > 0 {
+		deadline = time.Now().Add(limits.Timeout)
+	}
+
+	// генератор
+	thread, stop := newThread(SCRIPT_GENERATOR, limits.MaxSteps, deadline)
+	defer stop()
+	globals, err := load(thread, SCRIPT_GENERATOR, generator.Script, starlark.StringDict{
+		"input_data": input,
+		"amount":     starlark.MakeInt(0),
+		"start":      starlark.MakeInt(0),
+		"error":      starlark.None,
+	})
+	if err != nil {
+		return res, err
+	}
+	generate, err := function(SCRIPT_GENERATOR, globals, generator.FuncName, 3)
+	if err != nil {
+		return res, err
+	}
+
+	out, err := starlark.Call(thread, generate, starlark.Tuple{input, starlark.MakeInt(1), starlark.MakeInt(0)}, nil)
+	if err != nil {
+		return res, scriptError(SCRIPT_GENERATOR, STAGE_DRY_RUN, err)
+	}
+	status, generated, err := starlarkdata.StatusAndData(out)
+	if err != nil {
+		return res, &protocol.ScriptError{Script: SCRIPT_GENERATOR, Stage: STAGE_DRY_RUN, Msg: err.Error()}
+	}
+	switch status {
+	case "ok":
+		if err = checkSize(generated, limits.MaxResultBytes); err != nil {
+			return res, err
+		}
+	case "empty":
+	case "error":
+		return res, &protocol.ScriptError{Script: SCRIPT_GENERATOR, Stage: STAGE_DRY_RUN, Msg: fmt.Sprintf("generate returned error: %s", generated)}
+	default:
+		return res, &protocol.ScriptError{Script: SCRIPT_GENERATOR, Stage: STAGE_DRY_RUN, Msg: fmt.Sprintf("unknown generate status: %s", status)}
+	}
+
+	if fn, ok := globals[SIZE_FUNC_NAME]; ok {
+		if res.Size, err = size(thread, fn, input); err != nil {
+			return res, err
+		}
+		res.Sized = true
+	}
+
+	// решатель: модулю подставляются данные пробного генератора, как на слейве
+	if status != "ok" {
+		generated = starlark.None
+	}
+	thread, stop = newThread(SCRIPT_COMPUTE, limits.MaxSteps, deadline)
+	defer stop()
+	globals, err = load(thread, SCRIPT_COMPUTE, compute.Script, starlark.StringDict{
+		"input_data": generated,
+		"error":      starlark.None,
+	})
+	if err != nil {
+		return res, err
+	}
+	if _, err = function(SCRIPT_COMPUTE, globals, compute.FuncName, 1); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// newThread - поток проверки скрипта, отменяется по наступлении deadline. stop останавливает таймер
+func newThread(name string, maxSteps uint64, deadline time.Time) (*starlark.Thread, func()) {
+	thread := &starlark.Thread{
+		Name:  name,
+		Print: func(_ *starlark.Thread, msg string) { log.Printf("[SCRIPT][VALIDATE][%s] %s\n", name, msg) },
+	}
+	if maxSteps > 0 {
+		thread.SetMaxExecutionSteps(maxSteps)
+	}
+	if deadline.IsZero() {
+		return thread, func() {}
+	}
+
+	timer := time.AfterFunc(time.Until(deadline), func() { thread.Cancel("validation timeout") })
+
+	return thread, func() { timer.Stop() }
+}
+
+// checkSize - данные пробного вызова генератора не больше maxBytes в JSON, как результат подзадачи на слейве
+func checkSize(v starlark.Value, maxBytes int) error {
+	if maxBytes <= 0 {
+		return nil
+	}
+
+	goData, err := starlarkdata.ToGo(v)
+	if err != nil {
+		return &protocol.ScriptError{Script: SCRIPT_GENERATOR, Stage: STAGE_DRY_RUN, Msg: err.Error()}
+	}
+	dataBytes, err := json.Marshal(goData)
+	if err != nil {
+		return &protocol.ScriptError{Script: SCRIPT_GENERATOR, Stage: STAGE_DRY_RUN, Msg: err.Error()}
+	}
+	if len(dataBytes) > maxBytes {
+		return &protocol.ScriptError{Script: SCRIPT_GENERATOR, Stage: STAGE_DRY_RUN, Msg: fmt.Sprintf("generated data size %d bytes exceeds limit %d bytes", len(dataBytes), maxBytes)}
+	}
+
+	return nil
+}
+
+// load - разбор, резолв и исполнение модуля с теми же опциями, что и на слейве
+func load(thread *starlark.Thread, name, src string, predeclared starlark.StringDict) (starlark.StringDict, error) {
+	isPredeclared := func(name string) bool {
+		_, ok := predeclaredNames[name]
+		return ok
+	}
+
+	_, program, err := starlark.SourceProgramOptions(&syntax.FileOptions{}, name+".star", src, isPredeclared)
+	if err != nil {
+		return nil, scriptError(name, STAGE_SYNTAX, err)
+	}
+	globals, err := program.Init(thread, predeclared)
+	if err != nil {
+		return nil, scriptError(name, STAGE_EXEC, err)
+	}
+
+	return globals, nil
+}
+
+// function - функция модуля, которую можно вызвать с nArgs позиционными аргументами
+func function(name string, globals starlark.StringDict, funcName string, nArgs int) (*starlark.Function, error) {
+	v, ok := globals[funcName]
+	if !ok {
+		return nil, &protocol.ScriptError{Script: name, Stage: STAGE_SIGNATURE, Msg: fmt.Sprintf("function %s is not defined", funcName)}
+	}
+	fn, ok := v.(*starlark.Function)
+	if !ok {
+		return nil, &protocol.ScriptError{Script: name, Stage: STAGE_SIGNATURE, Msg: fmt.Sprintf("%s is %s, not a function", funcName, v.Type())}
+	}
+
+	positional := fn.NumParams() - fn.NumKwonlyParams()
+	if fn.HasVarargs() {
+		positional--
+	}
+	if fn.HasKwargs() {
+		positional--
+	}
+	required := 0
+	for i := 0; i < positional; i++ {
+		if fn.ParamDefault(i) == nil {
+			required++
+		}
+	}
+	mandatoryKwonly := 0
+	for i := positional; i < positional+fn.NumKwonlyParams(); i++ {
+		if fn.ParamDefault(i) == nil {
+			mandatoryKwonly++
+		}
+	}
+
+	if required > nArgs || (positional < nArgs && !fn.HasVarargs()) || mandatoryKwonly > 0 {
+		pos := fn.Position()
+		return nil, &protocol.ScriptError{
+			Script: name,
+			Stage:  STAGE_SIGNATURE,
+			Msg:    fmt.Sprintf("function %s must accept %d positional arguments, it has %d required and %d positional parameters", funcName, nArgs, required, positional),
+			Line:   pos.Line,
+			Col:    pos.Col,
+		}
+	}
+
+	return fn, nil
+}
+
+// size - вызов size(input_data), результат - целое в диапазоне uint32
+func size(thread *starlark.Thread, fn starlark.Value, input starlark.Value) (uint32, error) {
+	out, err := starlark.Call(thread, fn, starlark.Tuple{input}, nil)
+	if err != nil {
+		return 0, scriptError(SCRIPT_GENERATOR, STAGE_SIZE, err)
+	}
+	n, ok := out.(starlark.Int)
+	if !ok {
+		return 0, &protocol.ScriptError{Script: SCRIPT_GENERATOR, Stage: STAGE_SIZE, Msg: fmt.Sprintf("%s must return int, got %s", SIZE_FUNC_NAME, out.Type())}
+	}
+	v, exact := n.Int64()
+	if !exact || v < 0 || v > math.MaxUint32 {
+		return 0, &protocol.ScriptError{Script: SCRIPT_GENERATOR, Stage: STAGE_SIZE, Msg: fmt.Sprintf("%s out of range: %s", SIZE_FUNC_NAME, n)}
+	}
+
+	return uint32(v), nil
+}
+
+// scriptError - ошибка разбора, резолва или исполнения с позицией в скрипте
+func scriptError(name, stage string, err error) *protocol.ScriptError {
+	e := &protocol.ScriptError{Script: name, Stage: stage, Msg: err.Error()}
+
+	var pos syntax.Position
+	var syntaxErr syntax.Error
+	var resolveErr resolve.ErrorList
+	var evalErr *starlark.EvalError
+	switch {
+	case errors.As(err, &syntaxErr):
+		pos, e.Msg = syntaxErr.Pos, syntaxErr.Msg
+	case errors.As(err, &resolveErr):
+		e.Stage = STAGE_RESOLVE
+		pos, e.Msg = resolveErr[0].Pos, resolveErr[0].Msg
+	case errors.As(err, &evalErr):
+		e.Msg = evalErr.Msg
+		// позиция - самый глубокий кадр в самом скрипте, а не во встроенной функции
+		for i := len(evalErr.CallStack) - 1; i >= 0; i-- {
+			if frame := evalErr.CallStack[i]; frame.Pos.Filename() == name+".star" {
+				pos = frame.Pos
+				break
+			}
+		}
+	}
+	if pos.IsValid() {
+		e.Line, e.Col = pos.Line, pos.Col
+	}
+
+	return e
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package server
+
+import "net"
+
+// connClosed - на этой платформе закрытие соединения не проверяется, ответ отдается до истечения ожидания
+func connClosed(conn net.Conn) bool {
+	return false
+}
@@ -0,0 +1,37 @@
+//go:build unix
+
+package server
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// connClosed - клиент закрыл соединение. Сокет читается с MSG_PEEK без ожидания, поэтому данные в нем не трогаются
+func connClosed(conn net.Conn) bool {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return false
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return false
+	}
+
+	closed := false
+	buf := make([]byte, 1)
+	err = raw.Read(func(fd uintptr) bool {
+		// сокеты в Go неблокирующие: EAGAIN - соединение живо и данных нет, 0 байт - клиент закрыл соединение
+		n, _, err := syscall.Recvfrom(int(fd), buf, syscall.MSG_PEEK)
+		switch {
+		case err == nil:
+			closed = n == 0
+		case !errors.Is(err, syscall.EAGAIN) && !errors.Is(err, syscall.EINTR):
+			closed = true
+		}
+		return true
+	})
+
+	return closed || err != nil
+}
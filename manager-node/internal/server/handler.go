@@ -1,67 +1,100 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/valyala/fasthttp"
 	manager_client "manager-node/internal/manager-client"
-	"manager-node/pkg/model"
+	"net"
 	"net/http"
+	"protocol"
+	"time"
 )
 
 // regNodeMaster - регистрация мастер ноды
-func (s *Server) regNodeMaster(method string, body []byte, args *fasthttp.Args) error {
+func (s *Server) regNodeMaster(method string, body []byte, args *fasthttp.Args, remoteIP net.IP) error {
 	if method != http.MethodPost {
 		return errMethodNotAllowed
 	}
 
-	var req model.Node
+	var req protocol.Node
 	err := json.Unmarshal(body, &req)
 	if err != nil {
 		return err
 	}
+	req.Url = manager_client.ResolveNodeAddr(req.Url, remoteIP)
 
 	return s.managerCli.RegisterMaster(req)
 }
 
 // regNodeSlave - регистрация слейв ноды
-func (s *Server) regNodeSlave(method string, body []byte, args *fasthttp.Args) error {
+func (s *Server) regNodeSlave(method string, body []byte, args *fasthttp.Args, remoteIP net.IP) error {
 	if method != http.MethodPost {
 		return errMethodNotAllowed
 	}
 
-	var req model.Node
+	var req protocol.Node
 	err := json.Unmarshal(body, &req)
 	if err != nil {
 		return err
 	}
+	req.Url = manager_client.ResolveNodeAddr(req.Url, remoteIP)
 
 	return s.managerCli.RegisterSlave(req)
 }
 
-// removeNode - удаление ноды
-func (s *Server) removeNode(method string, body []byte, args *fasthttp.Args) error { //todo
+// removeNode - снятие ноды с учета при ее остановке. С drain=true слейв только перестает получать подзадачи
+func (s *Server) removeNode(method string, body []byte, args *fasthttp.Args) error {
 	if method != http.MethodDelete {
 		return errMethodNotAllowed
 	}
 
-	//uuid := string(args.Peek("uuid"))
+	uuid := string(args.Peek("uuid"))
+	if uuid == "" {
+		return errors.New("node uuid is required")
+	}
 
-	return nil
+	err := s.managerCli.RemoveNode(uuid, args.GetBool("drain"))
+	if errors.Is(err, manager_client.ErrNodeNotFound) {
+		return errNotFound
+	}
+
+	return err
 }
 
-// addTask - добавление задачи
-func (s *Server) addTask(method string, body []byte, args *fasthttp.Args) error {
+// heartbeat - сигнал жизни ноды. На незарегистрированную ноду отвечает 404 "unknown node"
+func (s *Server) heartbeat(method string, body []byte, args *fasthttp.Args) error {
 	if method != http.MethodPost {
 		return errMethodNotAllowed
 	}
-	var req manager_client.TaskConfig
+
+	var req protocol.Heartbeat
 	err := json.Unmarshal(body, &req)
 	if err != nil {
 		return err
 	}
 
-	return s.managerCli.SetTask(req)
+	return s.managerCli.Heartbeat(req)
+}
+
+// addTask - добавление задачи, в ответ отдается uuid созданной задачи
+func (s *Server) addTask(method string, body []byte, args *fasthttp.Args) ([]byte, error) {
+	if method != http.MethodPost {
+		return nil, errMethodNotAllowed
+	}
+	var req protocol.TaskConfig
+	err := json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	taskUuid, err := s.managerCli.SetTask(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(protocol.AddTaskResp{TaskUUID: taskUuid})
 }
 
 // closeTask - закрытие задачи от мастер ноды
@@ -72,19 +105,56 @@ func (s *Server) closeTask(method string, body []byte, args *fasthttp.Args) erro
 
 	uuid := string(args.Peek("uuid"))
 	if uuid == "" {
-		return errors.New("master uuid is required")
+		return errors.New("task uuid is required")
 	}
 
 	return s.managerCli.CloseTask(uuid)
 }
 
+// taskStatus - состояние задачи по ее uuid
+func (s *Server) taskStatus(method string, body []byte, args *fasthttp.Args) ([]byte, error) {
+	if method != http.MethodGet {
+		return nil, errMethodNotAllowed
+	}
+
+	uuid := string(args.Peek("uuid"))
+	if uuid == "" {
+		return nil, errors.New("task uuid is required")
+	}
+
+	status, err := s.managerCli.GetTaskStatus(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(status)
+}
+
+// listTasks - все задачи менеджера
+func (s *Server) listTasks(method string, body []byte, args *fasthttp.Args) ([]byte, error) {
+	if method != http.MethodGet {
+		return nil, errMethodNotAllowed
+	}
+
+	return json.Marshal(s.managerCli.ListTasks())
+}
+
+// listNodes - зарегистрированные ноды
+func (s *Server) listNodes(method string, body []byte, args *fasthttp.Args) ([]byte, error) {
+	if method != http.MethodGet {
+		return nil, errMethodNotAllowed
+	}
+
+	return json.Marshal(s.managerCli.ListNodes())
+}
+
 // completeSubTask - подтверждение от слейв ноды, о том что подзадача решена
 func (s *Server) completeSubTask(method string, body []byte, args *fasthttp.Args) error {
 	if method != http.MethodPost {
 		return errMethodNotAllowed
 	}
 
-	var req model.CompleteSubtaskRequest
+	var req protocol.CompleteSubtaskRequest
 	err := json.Unmarshal(body, &req)
 	if err != nil {
 		return err
@@ -99,19 +169,107 @@ func (s *Server) alertSubtaskError(method string, body []byte, args *fasthttp.Ar
 		return errMethodNotAllowed
 	}
 
-	var req ErrorSubtaskReq
+	var req protocol.ErrorSubtaskRequest
 	err := json.Unmarshal(body, &req)
 	if err != nil {
 		return err
 	}
 
-	s.managerCli.AlertSubtaskError(req.SubtaskUUID, req.SlaveUUID, req.Error)
+	if req.Type == "" {
+		req.Type = protocol.ERROR_SCRIPT
+	}
+	s.managerCli.ReportSubtaskError(req.SubtaskUUID, req.SlaveUUID, req.Type, req.Error)
 
 	return nil
 }
 
-type ErrorSubtaskReq struct {
-	SlaveUUID   string `json:"SlaveUUID"`
-	SubtaskUUID string `json:"SubtaskUUID"`
-	Error       string `json:"Error"`
+/*
+nextSubtask - длинный опрос слейва в режиме pull (?uuid=&free=&wait=), в ответ отдается подзадача и отмены подзадач слейва
+
+Запрос ждет работы до 'PULL_TIMEOUT' или до wait, если клиент ждет ответа меньше. Если работы так и не
+появилось - ответ 204 без тела. При free=0 подзадача не выдается, запрос ждет только отмен или освобождения
+слота слейва. Если слейв закрыл соединение, ожидание прекращается, а уже выданная подзадача сразу
+возвращается в очередь. Незарегистрированному слейву отвечает 404 "unknown node", снимаемому с учета - 503
+*/
+func (s *Server) nextSubtask(method string, body []byte, args *fasthttp.Args, ctx *fasthttp.RequestCtx) ([]byte, error) {
+	if method != http.MethodGet {
+		return nil, errMethodNotAllowed
+	}
+
+	uuid := string(args.Peek("uuid"))
+	if uuid == "" {
+		return nil, errors.New("slave uuid is required")
+	}
+	// слейвы без параметра free спрашивают только когда у них есть свободный воркер
+	free := !args.Has("free") || args.GetUintOrZero("free") > 0
+	wait := s.Cfg.PullTimeout
+	if d, err := time.ParseDuration(string(args.Peek("wait"))); err == nil && d > 0 && d < wait {
+		wait = d
+	}
+
+	// RequestCtx отменяется только при остановке сервера, уход клиента проверяется по соединению
+	done, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	go watchConn(done, cancel, ctx.Conn())
+
+	resp, err := s.managerCli.NextSubtask(done, uuid, free)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Subtask == nil && len(resp.Cancel) == 0 {
+		return nil, errNoWork
+	}
+	if connClosed(ctx.Conn()) {
+		s.managerCli.ReturnNext(uuid, resp)
+		return nil, errNoWork
+	}
+
+	return json.Marshal(resp)
+}
+
+// watchConn - отмена длинного опроса, если клиент закрыл соединение
+func watchConn(ctx context.Context, cancel context.CancelFunc, conn net.Conn) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if connClosed(conn) {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+/*
+blob - хранилище скриптов и данных задач по содержимому
+
+POST - загрузка блоба, в ответ отдается его id (sha256). GET ?id= - содержимое блоба
+*/
+func (s *Server) blob(method string, body []byte, args *fasthttp.Args) ([]byte, error) {
+	switch method {
+	case http.MethodPost:
+		if len(body) == 0 {
+			return nil, errors.New("blob is empty")
+		}
+		return json.Marshal(protocol.BlobResp{ID: s.managerCli.PutBlob(body)})
+
+	case http.MethodGet:
+		id := string(args.Peek("id"))
+		if id == "" {
+			return nil, errors.New("blob id is required")
+		}
+		data, err := s.managerCli.GetBlob(id)
+		if err != nil {
+			return nil, errNotFound
+		}
+		return data, nil
+
+	default:
+		return nil, errMethodNotAllowed
+	}
 }
@@ -1,31 +1,41 @@
 package server
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/valyala/fasthttp"
 	"manager-node/internal/utils"
+	"protocol"
 	"strings"
 	"unicode/utf8"
 )
 
+// пути API менеджера - из общего протокола
 const (
 	// Nodes
-	REGISTER_NODE_MASTER_PATH = "/node/register/master"
-	REGISTER_NODE_SLAVE_PATH  = "/node/register/slave"
-	REMOVE_NODE_PATH          = "/node/remove"
-	ADD_TASK_PATH             = "/task/add"
-	CLOSE_TASK_PATH           = "/task/close"
-	COMPLETE_SUBTASK_PATH     = "/subtask/complete"
-	ALERT_ERROR_SUBTASK_PATH  = "/subtask/error"
-
-	CHECK_TASK_STATUS = "/task/status"
-
-	V1 = "/api/v1"
+	REGISTER_NODE_MASTER_PATH = protocol.MANAGER_REGISTER_MASTER_PATH
+	REGISTER_NODE_SLAVE_PATH  = protocol.MANAGER_REGISTER_SLAVE_PATH
+	REMOVE_NODE_PATH          = protocol.MANAGER_REMOVE_NODE_PATH
+	HEARTBEAT_PATH            = protocol.MANAGER_HEARTBEAT_PATH
+	LIST_NODES_PATH           = protocol.MANAGER_LIST_NODES_PATH
+	ADD_TASK_PATH             = protocol.MANAGER_ADD_TASK_PATH
+	CLOSE_TASK_PATH           = protocol.MANAGER_CLOSE_TASK_PATH
+	COMPLETE_SUBTASK_PATH     = protocol.MANAGER_COMPLETE_SUBTASK_PATH
+	ALERT_ERROR_SUBTASK_PATH  = protocol.MANAGER_ERROR_SUBTASK_PATH
+	NEXT_SUBTASK_PATH         = protocol.MANAGER_NEXT_SUBTASK_PATH
+
+	CHECK_TASK_STATUS = protocol.MANAGER_TASK_STATUS_PATH
+	LIST_TASKS_PATH   = protocol.MANAGER_LIST_TASKS_PATH
+
+	BLOB_PATH = protocol.MANAGER_BLOB_PATH
+
+	V1 = protocol.API_V1
 )
 
 var (
 	errNotFound         = errors.New("not found")
 	errMethodNotAllowed = errors.New("method not allowed")
+	errNoWork           = errors.New("no work") // длинный опрос истек без подзадачи, ответ 204 без тела
 )
 
 func (s *Server) Router(ctx *fasthttp.RequestCtx) {
@@ -43,6 +53,18 @@ func (s *Server) Router(ctx *fasthttp.RequestCtx) {
 
 }
 
+// errorBody - текст ошибки, а для отклоненных скриптов - JSON с позицией ошибки
+func errorBody(err error) []byte {
+	var scriptErr *protocol.ScriptError
+	if errors.As(err, &scriptErr) {
+		if body, e := json.Marshal(scriptErr); e == nil {
+			return body
+		}
+	}
+
+	return []byte(err.Error())
+}
+
 func (s *Server) Handler(path string, ctx *fasthttp.RequestCtx) {
 
 	defer utils.Recovery("SERVER")
@@ -53,27 +75,39 @@ func (s *Server) Handler(path string, ctx *fasthttp.RequestCtx) {
 	var resp []byte
 	switch path {
 	case REGISTER_NODE_MASTER_PATH:
-		err = s.regNodeMaster(method, body, ctx.QueryArgs())
+		err = s.regNodeMaster(method, body, ctx.QueryArgs(), ctx.RemoteIP())
 	case REGISTER_NODE_SLAVE_PATH:
-		err = s.regNodeSlave(method, body, ctx.QueryArgs())
+		err = s.regNodeSlave(method, body, ctx.QueryArgs(), ctx.RemoteIP())
 	case REMOVE_NODE_PATH:
 		err = s.removeNode(method, body, ctx.QueryArgs())
+	case HEARTBEAT_PATH:
+		err = s.heartbeat(method, body, ctx.QueryArgs())
+	case LIST_NODES_PATH:
+		resp, err = s.listNodes(method, body, ctx.QueryArgs())
 	case ADD_TASK_PATH:
-		err = s.addTask(method, body, ctx.QueryArgs())
+		resp, err = s.addTask(method, body, ctx.QueryArgs())
 	case CLOSE_TASK_PATH:
 		err = s.closeTask(method, body, ctx.QueryArgs())
+	case CHECK_TASK_STATUS:
+		resp, err = s.taskStatus(method, body, ctx.QueryArgs())
+	case LIST_TASKS_PATH:
+		resp, err = s.listTasks(method, body, ctx.QueryArgs())
 	case COMPLETE_SUBTASK_PATH:
 		err = s.completeSubTask(method, body, ctx.QueryArgs())
 	case ALERT_ERROR_SUBTASK_PATH:
 		err = s.alertSubtaskError(method, body, ctx.QueryArgs())
+	case NEXT_SUBTASK_PATH:
+		resp, err = s.nextSubtask(method, body, ctx.QueryArgs(), ctx)
+	case BLOB_PATH:
+		resp, err = s.blob(method, body, ctx.QueryArgs())
 
 	default:
 		err = errNotFound
 
 	}
 
-	if err != nil {
-		resp = []byte(err.Error())
+	if err != nil && err != errNoWork {
+		resp = errorBody(err)
 	}
 	setStatusCode(ctx, err)
 	if resp != nil {
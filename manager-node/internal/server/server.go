@@ -2,12 +2,14 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/valyala/fasthttp"
 	"log"
 	"manager-node/internal/config"
 	manager_client "manager-node/internal/manager-client"
 	"net/http"
+	"protocol"
 )
 
 type Server struct {
@@ -60,10 +62,20 @@ func (s *Server) Start() {
 }
 
 func setStatusCode(ctx *fasthttp.RequestCtx, err error) {
+	var scriptErr *protocol.ScriptError
+	if errors.As(err, &scriptErr) {
+		ctx.SetStatusCode(fasthttp.StatusUnprocessableEntity)
+		return
+	}
+
 	if err != nil {
 		switch err {
-		case errNotFound:
+		case errNoWork:
+			ctx.SetStatusCode(fasthttp.StatusNoContent)
+		case errNotFound, protocol.ErrUnknownNode:
 			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		case protocol.ErrDraining:
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
 		case errMethodNotAllowed:
 			ctx.SetStatusCode(fasthttp.StatusMethodNotAllowed)
 		default:
@@ -76,7 +88,7 @@ func setStatusCode(ctx *fasthttp.RequestCtx, err error) {
 
 func (s *Server) initRoutsServerPrivate() http.Handler {
 	privateMux := http.NewServeMux()
-	privateMux.HandleFunc("/health", func(writer http.ResponseWriter, request *http.Request) { writer.WriteHeader(http.StatusOK) })
+	privateMux.HandleFunc(protocol.HEALTH_PATH, func(writer http.ResponseWriter, request *http.Request) { writer.WriteHeader(http.StatusOK) })
 
 	return privateMux
 }
@@ -0,0 +1,404 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	opSaveNode         = "node"
+	opDeleteNode       = "node_delete"
+	opSaveTask         = "task"
+	opSaveTaskProgress = "task_progress"
+	opDeleteTask       = "task_delete"
+	opSaveSubtask      = "subtask"
+	opDeleteSubtask    = "subtask_delete"
+
+	compactThreshold = 10000                 // после скольких записей в журнал он переписывается снимком
+	syncInterval     = 20 * time.Millisecond // как часто накопленные записи сбрасываются на диск
+)
+
+// walEntry - одна запись журнала
+type walEntry struct {
+	Op       string         `json:"Op"`
+	UUID     string         `json:"UUID,omitempty"`
+	Node     *NodeRecord    `json:"Node,omitempty"`
+	Task     *TaskRecord    `json:"Task,omitempty"`
+	Progress *TaskProgress  `json:"Progress,omitempty"`
+	Subtask  *SubtaskRecord `json:"Subtask,omitempty"`
+}
+
+/*
+FileStore - хранилище на основе журнала упреждающей записи (WAL)
+
+Каждое изменение дописывается в файл отдельной JSON строкой. На диск записи сбрасываются пачкой
+фоновым воркером раз в 'syncInterval', чтобы fsync не выполнялся под блокировкой менеджера:
+при падении процесса записи остаются в кеше ОС, при отключении питания теряется не больше 'syncInterval'.
+При открытии и каждые 'compactThreshold' записей журнал переписывается компактным снимком.
+Снимок пишется без fs.mu: под блокировкой только копируется состояние и подменяется файл
+*/
+type FileStore struct {
+	path  string
+	file  *os.File
+	mem   *MemoryStore
+	ops   int
+	dirty bool // есть записи, еще не сброшенные на диск
+
+	compacting bool     // пишется снимок, новые записи копятся в pending
+	pending    [][]byte // записи, сделанные за время записи снимка, дописываются в его конец
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	stopped  chan struct{}
+
+	mu sync.Mutex
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	fs := &FileStore{
+		path:    path,
+		mem:     NewMemoryStore(),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	if err := fs.replay(); err != nil {
+		return nil, err
+	}
+	state, err := fs.mem.Load()
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := fs.writeSnapshot(state)
+	if err != nil {
+		return nil, err
+	}
+	if err = fs.swap(tmp, nil); err != nil {
+		return nil, err
+	}
+
+	go fs.syncWorker()
+
+	return fs, nil
+}
+
+// replay - проигрывание журнала в память. Оборванная последняя запись (падение во время записи) пропускается
+func (fs *FileStore) replay() error {
+	f, err := os.Open(fs.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		data, err := r.ReadBytes('\n')
+		if len(data) != 0 {
+			var e walEntry
+			if errJson := json.Unmarshal(data, &e); errJson != nil {
+				if err == io.EOF {
+					log.Printf("[STORE][WARN] skip broken tail of %s at line %d: %v\n", fs.path, line, errJson)
+					return nil
+				}
+				return fmt.Errorf("store %s line %d: %w", fs.path, line, errJson)
+			}
+			if errApply := fs.apply(e); errApply != nil {
+				return fmt.Errorf("store %s line %d: %w", fs.path, line, errApply)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (fs *FileStore) apply(e walEntry) error {
+	switch e.Op {
+	case opSaveNode:
+		if e.Node == nil {
+			return errors.New("empty node")
+		}
+		return fs.mem.SaveNode(*e.Node)
+	case opDeleteNode:
+		return fs.mem.DeleteNode(e.UUID)
+	case opSaveTask:
+		if e.Task == nil {
+			return errors.New("empty task")
+		}
+		return fs.mem.SaveTask(*e.Task)
+	case opSaveTaskProgress:
+		if e.Progress == nil {
+			return errors.New("empty task progress")
+		}
+		return fs.mem.SaveTaskProgress(*e.Progress)
+	case opDeleteTask:
+		return fs.mem.DeleteTask(e.UUID)
+	case opSaveSubtask:
+		if e.Subtask == nil {
+			return errors.New("empty subtask")
+		}
+		return fs.mem.SaveSubtask(*e.Subtask)
+	case opDeleteSubtask:
+		return fs.mem.DeleteSubtask(e.UUID)
+	default:
+		return fmt.Errorf("unknown op %q", e.Op)
+	}
+}
+
+// writeSnapshot - запись состояния во временный файл рядом с журналом, fs.mu не нужен. Файл остается открытым для swap
+func (fs *FileStore) writeSnapshot(state State) (*os.File, error) {
+	tmpPath := fs.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	w := bufio.NewWriter(tmp)
+	write := func(e walEntry) error {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+
+	for _, node := range state.Nodes {
+		node := node
+		if err == nil {
+			err = write(walEntry{Op: opSaveNode, Node: &node})
+		}
+	}
+	for _, task := range state.Tasks {
+		task := task
+		if err == nil {
+			err = write(walEntry{Op: opSaveTask, Task: &task})
+		}
+	}
+	for _, subtask := range state.Subtasks {
+		subtask := subtask
+		if err == nil {
+			err = write(walEntry{Op: opSaveSubtask, Subtask: &subtask})
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	return tmp, nil
+}
+
+/*
+swap - атомарная подмена журнала снимком. Вызывается под fs.mu
+
+tail - записи, сделанные после копирования состояния: они дописываются в конец снимка
+и сбрасываются на диск вместе с остальными записями воркером
+*/
+func (fs *FileStore) swap(tmp *os.File, tail [][]byte) error {
+	var err error
+	for _, line := range tail {
+		if _, err = tmp.Write(line); err != nil {
+			break
+		}
+	}
+	if errClose := tmp.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if fs.file != nil {
+		fs.file.Close()
+	}
+	if err = os.Rename(tmp.Name(), fs.path); err != nil {
+		return err
+	}
+
+	fs.file, err = os.OpenFile(fs.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	fs.ops = len(tail)
+	fs.dirty = len(tail) != 0
+
+	return err
+}
+
+/*
+compact - сжатие журнала снимком из фонового воркера
+
+Под fs.mu копируется состояние, снимок пишется и сбрасывается на диск без блокировки.
+Записи за это время идут в старый журнал как обычно и копятся в pending, при подмене они дописываются в снимок
+*/
+func (fs *FileStore) compact() error {
+	fs.mu.Lock()
+	state, err := fs.mem.Load()
+	if err != nil {
+		fs.mu.Unlock()
+		return err
+	}
+	fs.compacting = true
+	fs.mu.Unlock()
+
+	tmp, err := fs.writeSnapshot(state)
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	tail := fs.pending
+	fs.compacting, fs.pending = false, nil
+	if err != nil {
+		// повтор через следующие 'compactThreshold' записей
+		fs.ops = 0
+		return err
+	}
+
+	return fs.swap(tmp, tail)
+}
+
+// append - применение изменения в памяти и дозапись его в журнал
+func (fs *FileStore) append(e walEntry) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.file == nil {
+		return errors.New("store is closed")
+	}
+
+	if err := fs.apply(e); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line := append(data, '\n')
+	if _, err = fs.file.Write(line); err != nil {
+		return err
+	}
+	if fs.compacting {
+		fs.pending = append(fs.pending, line)
+	}
+	fs.dirty = true
+	fs.ops++
+
+	return nil
+}
+
+/*
+syncWorker - групповой сброс журнала на диск и его сжатие
+
+fsync выполняется без fs.mu, чтобы запись в журнал в это время не ждала диска
+*/
+func (fs *FileStore) syncWorker() {
+	defer close(fs.stopped)
+
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-fs.stop:
+			return
+		case <-ticker.C:
+		}
+
+		fs.mu.Lock()
+		compact := fs.ops >= compactThreshold
+		fs.mu.Unlock()
+		if compact {
+			if err := fs.compact(); err != nil {
+				log.Println("[STORE][ERROR] compact:", err)
+			}
+		}
+
+		fs.mu.Lock()
+		file, dirty := fs.file, fs.dirty
+		fs.dirty = false
+		fs.mu.Unlock()
+
+		if !dirty || file == nil {
+			continue
+		}
+		if err := file.Sync(); err != nil {
+			log.Println("[STORE][ERROR] sync:", err)
+		}
+	}
+}
+
+func (fs *FileStore) SaveNode(node NodeRecord) error {
+	return fs.append(walEntry{Op: opSaveNode, Node: &node})
+}
+
+func (fs *FileStore) DeleteNode(uuid string) error {
+	return fs.append(walEntry{Op: opDeleteNode, UUID: uuid})
+}
+
+func (fs *FileStore) SaveTask(task TaskRecord) error {
+	return fs.append(walEntry{Op: opSaveTask, Task: &task})
+}
+
+func (fs *FileStore) SaveTaskProgress(progress TaskProgress) error {
+	return fs.append(walEntry{Op: opSaveTaskProgress, Progress: &progress})
+}
+
+func (fs *FileStore) DeleteTask(uuid string) error {
+	return fs.append(walEntry{Op: opDeleteTask, UUID: uuid})
+}
+
+func (fs *FileStore) SaveSubtask(subtask SubtaskRecord) error {
+	return fs.append(walEntry{Op: opSaveSubtask, Subtask: &subtask})
+}
+
+func (fs *FileStore) DeleteSubtask(uuid string) error {
+	return fs.append(walEntry{Op: opDeleteSubtask, UUID: uuid})
+}
+
+func (fs *FileStore) Load() (State, error) {
+	return fs.mem.Load()
+}
+
+func (fs *FileStore) Close() error {
+	fs.stopOnce.Do(func() { close(fs.stop) })
+	<-fs.stopped
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.file == nil {
+		return nil
+	}
+	err := fs.file.Sync()
+	if errClose := fs.file.Close(); err == nil {
+		err = errClose
+	}
+	fs.file = nil
+
+	return err
+}
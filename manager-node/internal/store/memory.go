@@ -0,0 +1,100 @@
+package store
+
+import "sync"
+
+// MemoryStore - хранилище в памяти процесса. Ничего не переживает, используется в тестах и без STORE_PATH
+type MemoryStore struct {
+	state State
+	mu    sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{state: newState()}
+}
+
+func (m *MemoryStore) SaveNode(node NodeRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.state.Nodes[node.Node.UUID] = node
+	return nil
+}
+
+func (m *MemoryStore) DeleteNode(uuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.state.Nodes, uuid)
+	return nil
+}
+
+func (m *MemoryStore) SaveTask(task TaskRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task.TaskProgress.UUID = task.UUID
+	m.state.Tasks[task.UUID] = task
+	return nil
+}
+
+func (m *MemoryStore) SaveTaskProgress(progress TaskProgress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if task, ok := m.state.Tasks[progress.UUID]; ok {
+		task.TaskProgress = progress
+		m.state.Tasks[progress.UUID] = task
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteTask(uuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.state.Tasks, uuid)
+	for subtaskUuid, subtask := range m.state.Subtasks {
+		if subtask.TaskUUID == uuid {
+			delete(m.state.Subtasks, subtaskUuid)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) SaveSubtask(subtask SubtaskRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.state.Subtasks[subtask.UUID] = subtask
+	return nil
+}
+
+func (m *MemoryStore) DeleteSubtask(uuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.state.Subtasks, uuid)
+	return nil
+}
+
+// Load - копия текущего состояния
+func (m *MemoryStore) Load() (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := newState()
+	for k, v := range m.state.Nodes {
+		state.Nodes[k] = v
+	}
+	for k, v := range m.state.Tasks {
+		state.Tasks[k] = v
+	}
+	for k, v := range m.state.Subtasks {
+		state.Subtasks[k] = v
+	}
+	return state, nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"encoding/json"
+	"protocol"
+)
+
+const (
+	NODE_MASTER = "master"
+	NODE_SLAVE  = "slave"
+)
+
+// Store - хранилище состояния менеджера, переживающее рестарт процесса
+type Store interface {
+	SaveNode(node NodeRecord) error
+	DeleteNode(uuid string) error
+
+	SaveTask(task TaskRecord) error
+	SaveTaskProgress(progress TaskProgress) error
+	DeleteTask(uuid string) error
+
+	SaveSubtask(subtask SubtaskRecord) error
+	DeleteSubtask(uuid string) error
+
+	Load() (State, error)
+	Close() error
+}
+
+// NodeRecord - зарегистрированная нода
+type NodeRecord struct {
+	Kind string        `json:"Kind"` // master/slave
+	Node protocol.Node `json:"Node"`
+}
+
+// TaskRecord - задача мастера вместе с ее прогрессом
+type TaskRecord struct {
+	UUID            string                   `json:"UUID"`
+	MasterUUID      string                   `json:"MasterUUID"`
+	TaskName        string                   `json:"TaskName"`
+	GeneratorScript protocol.ScriptConfig    `json:"GeneratorScript"`
+	ComputeScript   protocol.ScriptConfig    `json:"ComputeScript"`
+	Data            json.RawMessage          `json:"Data"`
+	Limits          protocol.ExecutionLimits `json:"Limits"`
+	TaskProgress
+}
+
+// TaskProgress - изменяемая часть задачи. Пишется отдельно, чтобы не переписывать скрипты и данные на каждом шаге
+type TaskProgress struct {
+	UUID      string `json:"UUID"`
+	Status    uint8  `json:"Status"`
+	Counter   uint32 `json:"Counter"`
+	Completed int    `json:"Completed"`
+	Failed    int    `json:"Failed"`
+	Exhausted bool   `json:"Exhausted,omitempty"` // генератор сообщил об окончании данных на позиции End
+	End       uint32 `json:"End,omitempty"`
+	Dropped   int    `json:"Dropped,omitempty"`
+	Sized     bool   `json:"Sized,omitempty"` // размер задачи известен из size(input_data)
+	Size      uint32 `json:"Size,omitempty"`
+	Solved    uint64 `json:"Solved,omitempty"` // сумма диапазонов, результаты которых приняты мастером
+}
+
+// SubtaskRecord - выданный диапазон подзадачи
+type SubtaskRecord struct {
+	UUID      string `json:"UUID"`
+	TaskUUID  string `json:"TaskUUID"`
+	SlaveUUID string `json:"SlaveUUID"`
+	Start     uint32 `json:"Start"`
+	Amount    uint32 `json:"Amount"`
+	ErrCount  int    `json:"ErrCount"`
+}
+
+// State - полный снимок хранилища
+type State struct {
+	Nodes    map[string]NodeRecord
+	Tasks    map[string]TaskRecord
+	Subtasks map[string]SubtaskRecord
+}
+
+func newState() State {
+	return State{
+		Nodes:    make(map[string]NodeRecord),
+		Tasks:    make(map[string]TaskRecord),
+		Subtasks: make(map[string]SubtaskRecord),
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"protocol"
+	apiclient "protocol/client"
+	"time"
+)
+
+// requestTimeout - ограничение на один запрос к мастеру или менеджеру
+const requestTimeout = 30 * time.Second
+
+// client - клиенты API мастера и менеджера из protocol/client
+type client struct {
+	master  *apiclient.Master
+	manager *apiclient.Manager
+	json    bool
+}
+
+func newClient(masterURL, managerURL string, json bool) *client {
+	return &client{
+		master:  apiclient.NewMaster(masterURL, requestTimeout),
+		manager: apiclient.NewManager(managerURL, requestTimeout),
+		json:    json,
+	}
+}
+
+// rejected - отклоненный мастером скрипт задачи выводится с позицией ошибки
+func rejected(err error) error {
+	var scriptErr *protocol.ScriptError
+	if errors.As(err, &scriptErr) {
+		return fmt.Errorf("job rejected: %v", scriptErr)
+	}
+
+	return err
+}
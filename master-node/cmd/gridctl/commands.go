@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"protocol"
+	"time"
+)
+
+func runSubmit(c *client, args []string) error {
+	fs := flag.NewFlagSet("submit", flag.ExitOnError)
+	generator := fs.String("generator", "", "generator .star script")
+	generateFunc := fs.String("generate-func", "generate", "generator function name")
+	compute := fs.String("compute", "", "compute .star script")
+	computeFunc := fs.String("compute-func", "compute", "compute function name")
+	reduceScript := fs.String("reduce", "", "reduce .star script for the reduce engine")
+	reduceFunc := fs.String("reduce-func", "reduce", "reduce function name")
+	key := fs.String("key", "", "key path for built-in reducers, e.g. data.cost")
+	k := fs.Int("k", 0, "K for the topk reducer")
+	ascending := fs.Bool("asc", false, "topk keeps the K smallest values")
+	sinkPath := fs.String("sink", "", "result file (or directory with -sink-dir) for the jsonl engine")
+	sinkDir := fs.Bool("sink-dir", false, "jsonl engine writes every subtask result to its own file")
+	input := fs.String("input", "", "JSON input data file")
+	engine := fs.String("engine", "", "result engine, master default if empty")
+	name := fs.String("name", "", "job name")
+	watch := fs.Bool("watch", false, "watch progress after submit")
+	fs.Parse(args)
+
+	if *generator == "" || *compute == "" || *input == "" {
+		return errors.New("-generator, -compute and -input are required")
+	}
+
+	generatorScript, err := os.ReadFile(*generator)
+	if err != nil {
+		return err
+	}
+	computeScript, err := os.ReadFile(*compute)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(*input)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return fmt.Errorf("%s is not valid JSON", *input)
+	}
+	if (*sinkPath != "" || *sinkDir) && *engine == "" {
+		*engine = "jsonl"
+	}
+	var reduce protocol.JobScript
+	if *reduceScript != "" {
+		script, err := os.ReadFile(*reduceScript)
+		if err != nil {
+			return err
+		}
+		reduce = protocol.JobScript{Script: string(script), FuncName: *reduceFunc}
+		if *engine == "" {
+			*engine = "reduce"
+		}
+	}
+
+	req := protocol.JobRequest{
+		Name:            *name,
+		Engine:          *engine,
+		GeneratorScript: protocol.JobScript{Script: string(generatorScript), FuncName: *generateFunc},
+		ComputeScript:   protocol.JobScript{Script: string(computeScript), FuncName: *computeFunc},
+		ReduceScript:    reduce,
+		Reducer:         protocol.ReducerOptions{Key: *key, K: *k, Ascending: *ascending},
+		Sink:            protocol.SinkOptions{Path: *sinkPath, Dir: *sinkDir},
+		Data:            data,
+	}
+	if req.Name == "" {
+		req.Name = *input
+	}
+
+	resp, err := c.master.AddJob(req)
+	if err != nil {
+		return rejected(err)
+	}
+
+	if c.json {
+		printJSON(resp)
+	} else {
+		printTable([]string{"JOB", "TASK"}, [][]string{{resp.JobID, resp.TaskUUID}})
+	}
+
+	if *watch {
+		return watchJob(c, resp.JobID, 2*time.Second)
+	}
+
+	return nil
+}
+
+func runWatch(c *client, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := fs.Duration("interval", 2*time.Second, "poll interval")
+	id, err := jobArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	return watchJob(c, id, *interval)
+}
+
+// watchJob - опрос состояния задачи, пока она решается. Каждое изменение выводится строкой
+func watchJob(c *client, id string, interval time.Duration) error {
+	var last string
+	for {
+		status, err := c.master.JobStatus(id)
+		if err != nil {
+			return err
+		}
+
+		if c.json {
+			printJSON(status)
+		} else {
+			cov := status.Coverage
+			line := fmt.Sprintf("%-8s  accepted: %-6d covered: %-8d prefix: %-8d dup: %-4d", status.Status, cov.Subtasks, cov.Covered, cov.Prefix, cov.Duplicates)
+			if m := status.Manager; m != nil {
+				line += fmt.Sprintf("  manager: %-8s counter: %-8d completed: %-6d in flight: %-4d failed: %-4d slaves: %d",
+					m.Status, m.Counter, m.Completed, m.InFlight, m.Failed, len(m.Slaves))
+				if m.Progress != nil {
+					line += fmt.Sprintf("  progress: %.1f%% of %d", *m.Progress, *m.Size)
+				}
+				if m.Exhausted {
+					line += fmt.Sprintf("  end: %d", m.End)
+				}
+			}
+			if status.Error != "" {
+				line += "  error: " + status.Error
+			}
+			if line != last {
+				fmt.Println(time.Now().Format("15:04:05"), line)
+				last = line
+			}
+		}
+
+		if status.Status != "solving" {
+			if s := status.Summary; s != nil && !c.json {
+				fmt.Printf("done: end %d, completed %d, failed %d, dropped %d\n", s.End, s.Completed, s.Failed, s.Dropped)
+			}
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+func runJobs(c *client, args []string) error {
+	jobs, err := c.master.ListJobs()
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		printJSON(jobs)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		rows = append(rows, []string{job.JobID, job.Name, job.Engine, job.Status, job.TaskUUID, job.CreatedAt.Format(time.DateTime), job.Error})
+	}
+	printTable([]string{"JOB", "NAME", "ENGINE", "STATUS", "TASK", "CREATED", "ERROR"}, rows)
+
+	return nil
+}
+
+func runResult(c *client, args []string) error {
+	fs := flag.NewFlagSet("result", flag.ExitOnError)
+	out := fs.String("o", "", "write result JSON to file")
+	id, err := jobArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.master.JobResult(id)
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		data, err := json.MarshalIndent(result.Result, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(*out, data, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "job %s (%s): result written to %s\n", result.JobID, result.Status, *out)
+		return nil
+	}
+
+	if c.json {
+		printJSON(result)
+		return nil
+	}
+
+	fmt.Printf("job:    %s\nstatus: %s\n", result.JobID, result.Status)
+	if result.Error != "" {
+		fmt.Printf("error:  %s\n", result.Error)
+	}
+	data, err := json.MarshalIndent(result.Result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("result: %s\n", data)
+
+	return nil
+}
+
+func runCancel(c *client, args []string) error {
+	fs := flag.NewFlagSet("cancel", flag.ExitOnError)
+	id, err := jobArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if err = c.master.CancelJob(id); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "job %s cancelled\n", id)
+
+	return nil
+}
+
+func runNodes(c *client, args []string) error {
+	nodes, err := c.manager.ListNodes()
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		printJSON(nodes)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(nodes))
+	for _, n := range nodes {
+		row := []string{n.Kind, n.UUID, protocol.HostPort(n.Url, n.PublicPort), n.Status, "", "", "", n.Version, ""}
+		if !n.LastSeen.IsZero() {
+			row[8] = time.Since(n.LastSeen).Truncate(time.Second).String() + " ago"
+		}
+		if n.Kind == protocol.NODE_KIND_SLAVE {
+			row[4] = fmt.Sprintf("%d/%d", n.FreeSlots, n.Slots)
+			row[5] = fmt.Sprint(n.Power)
+			row[6] = fmt.Sprintf("%.1f", n.Benchmark)
+		} else {
+			row[3] = fmt.Sprintf("%d tasks", n.ActiveTasks)
+		}
+		rows = append(rows, row)
+	}
+	printTable([]string{"KIND", "UUID", "ADDR", "STATUS", "FREE", "POWER", "BENCHMARK", "VERSION", "SEEN"}, rows)
+
+	return nil
+}
+
+func runTasks(c *client, args []string) error {
+	tasks, err := c.manager.ListTasks()
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		printJSON(tasks)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(tasks))
+	for _, t := range tasks {
+		rows = append(rows, []string{t.TaskUUID, t.MasterUUID, t.Status, fmt.Sprint(t.Counter), fmt.Sprint(t.Completed), fmt.Sprint(t.InFlight), fmt.Sprint(t.Failed)})
+	}
+	printTable([]string{"TASK", "MASTER", "STATUS", "COUNTER", "COMPLETED", "IN FLIGHT", "FAILED"}, rows)
+
+	return nil
+}
+
+// jobArg - id задачи первым аргументом, флаги команды после него
+func jobArg(fs *flag.FlagSet, args []string) (string, error) {
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return "", errors.New("job id is required")
+		}
+		return fs.Arg(0), nil
+	}
+
+	fs.Parse(args[1:])
+
+	return args[0], nil
+}
@@ -0,0 +1,85 @@
+/*
+gridctl - клиент кластера: постановка задач на мастер, наблюдение за ними и просмотр нод и задач менеджера.
+
+	gridctl [-master URL] [-manager URL] [-json] <команда> [аргументы]
+
+Команды:
+
+	submit -generator gen.star -compute compute.star -input data.json [-reduce reduce.star] [-engine tsp] [-key data.cost] [-k 10] [-sink results.jsonl] [-name NAME] [-watch]
+	watch JOB_ID        прогресс задачи, пока она не завершится
+	jobs                задачи мастера
+	result JOB_ID [-o FILE]
+	cancel JOB_ID
+	nodes               ноды менеджера
+	tasks               задачи менеджера
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(c *client, args []string) error
+}
+
+var commands = []command{
+	{"submit", "submit -generator FILE -compute FILE -input FILE [-reduce FILE] [-engine NAME] [-key PATH] [-k N] [-asc] [-sink PATH] [-sink-dir] [-name NAME] [-watch]", runSubmit},
+	{"watch", "watch JOB_ID [-interval 2s]", runWatch},
+	{"jobs", "jobs", runJobs},
+	{"result", "result JOB_ID [-o FILE]", runResult},
+	{"cancel", "cancel JOB_ID", runCancel},
+	{"nodes", "nodes", runNodes},
+	{"tasks", "tasks", runTasks},
+}
+
+func main() {
+	masterURL := flag.String("master", envOr("GRIDCTL_MASTER", "http://localhost:8085"), "master URL")
+	managerURL := flag.String("manager", envOr("GRIDCTL_MANAGER", "http://localhost:8080"), "manager URL")
+	jsonOut := flag.Bool("json", false, "print JSON instead of tables")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	c := newClient(*masterURL, *managerURL, *jsonOut)
+	name, args := flag.Arg(0), flag.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		if err := cmd.run(c, args); err != nil {
+			fmt.Fprintln(os.Stderr, "gridctl:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "gridctl: unknown command %q\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: gridctl [-master URL] [-manager URL] [-json] <command> [args]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintln(os.Stderr, "  "+cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nflags:")
+	flag.PrintDefaults()
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -2,14 +2,16 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"master-node/internal/config"
+	grpc_server "master-node/internal/grpc-server"
+	"master-node/internal/jobs"
 	"master-node/internal/server"
 	"master-node/internal/tasker"
-	tasker_impl "master-node/internal/tasker-impl"
+	"master-node/pkg/model"
 	"os"
 	"os/signal"
+	"protocol/client"
 	"syscall"
 	"time"
 )
@@ -23,105 +25,76 @@ func main() {
 	cfg := config.LoadConfig()
 	// ====================
 
-	// === tasker impl ===
-
-	taskImpl := tasker_impl.New(cfg)
-
-	// === === === === ===
-
-	//matrix := map[string][][]int{
-	//	"matrix": {
-	//		{0, 1, 2, 3, 2, 1},
-	//		{1, 0, 3, 1, 2, 2},
-	//		{2, 3, 0, 2, 3, 2},
-	//		{3, 1, 2, 0, 2, 2},
-	//		{2, 2, 3, 2, 0, 2},
-	//		{1, 2, 3, 2, 2, 0},
-	//	},
-	//}
-
-	//matrix := map[string][][]int{
-	//	"matrix": {
-	//		{0, 1, 2, 3, 2},
-	//		{1, 0, 3, 1, 2},
-	//		{2, 3, 0, 2, 3},
-	//		{3, 1, 2, 0, 2},
-	//		{2, 2, 3, 2, 0},
-	//	},
-	//}
+	// ===== Jobs =====
+	log.Println("[SERVICE] INITIALIZING JOBS")
+	js := jobs.New(cfg)
+	// =====================
 
-	//matrix := map[string][][]int{
-	//	"matrix": {
-	//		{0, 1, 2, 3},
-	//		{1, 0, 3, 1},
-	//		{2, 3, 0, 2},
-	//		{3, 1, 2, 0},
-	//	},
-	//}
+	// ====== Server ======
+	log.Println("[SERVICE] START SERVER")
+	srv := server.New(cfg, js)
+	log.Println("[SERVER] Start")
+	srv.Start()
 
-	matrix := map[string][][]int{
-		"matrix": {
-			{0, 10, 10, 1},
-			{10, 0, 10, 1},
-			{10, 10, 0, 1},
-			{1, 1, 1, 0},
-		},
+	// gRPC сервис для коллбеков менеджера, REST при этом остается
+	var grpcSrv *grpc_server.Server
+	if cfg.GrpcPort != "" {
+		grpcSrv = grpc_server.New(cfg, js)
+		log.Println("[GRPC SERVER] Start")
+		grpcSrv.Start()
 	}
+	// ====================
 
-	//matrix := map[string][][]int{
-	//	"matrix": {
-	//		{0, 0, 0, 10},
-	//		{0, 0, 10, 1},
-	//		{0, 10, 0, 1},
-	//		{10, 1, 1, 0},
-	//	},
-	//}
-
-	//// недостижимый случай
-	//matrix := map[string][][]int{
-	//	"matrix": {
-	//		{0, 0, 0, 0},
-	//		{0, 0, 1, 1},
-	//		{0, 1, 0, 1},
-	//		{0, 1, 1, 0},
-	//	},
-	//}
-
-	//matrix := map[string][][]int{
-	//	"matrix": {
-	//		{0, 1, 2},
-	//		{1, 0, 3},
-	//		{2, 3, 0},
-	//	},
-	//}
-	dataTask, err := json.Marshal(matrix)
-
-	// ===== Tasker =====
-	log.Println("[SERVICE] INITIALIZING TASKER")
-	//t, err := tasker.NewTasker(context.Background(), cfg, tasker.TaskEngineMock{})
-	t, err := tasker.NewTasker(context.Background(), cfg, taskImpl, dataTask)
+	err := tasker.RegNode(cfg)
 	if err != nil {
-		log.Fatal(err)
+		log.Println("[SERVICE][ERROR] register node:", err)
 	}
-	// =====================
 
-	err = t.Start()
+	// если регистрация не прошла или менеджер забыл мастер, он регистрируется заново по ответу на heartbeat
+	ctxHeartbeat, stopHeartbeat := context.WithCancel(context.Background())
+	hbManager, err := client.NewManagerAPI(cfg.Transport, cfg.ManagerURL, cfg.ManagerGrpcAddr, cfg.HeartbeatInterval)
 	if err != nil {
-		log.Fatalln(err)
+		log.Println("[HEARTBEAT][ERROR]", err)
+	} else {
+		go client.RunHeartbeat(ctxHeartbeat, hbManager, cfg.HeartbeatInterval, js.Heartbeat, func() error { return tasker.RegNode(cfg) })
 	}
 
-	// ====== Server ======
-	log.Println("[SERVICE] START SERVER")
-	srv := server.New(cfg, t)
-	log.Println("[SERVER] Start")
-	srv.Start()
-	// ====================
+	// задача при старте, если задан файл с данными. Остальные задачи ставятся через /job/add
+	if cfg.TaskDataPath != "" {
+		dataTask, err := os.ReadFile(cfg.TaskDataPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		_, err = js.Submit(model.JobRequest{Name: cfg.TaskDataPath, Data: dataTask})
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
 
 	<-stop
-	t.Stop()
+
+	// ===== Drain =====
+	// новые задачи не принимаются, поставленные дорешиваются. Не успевшие завершиться задачи закрываются
+	// на менеджере, после чего мастер снимается с учета, не дожидаясь проверки жизни
+	log.Println("[SERVICE] DRAINING NODE")
+	ctxDrain, cancelDrain := context.WithTimeout(context.Background(), cfg.DrainTimeout)
+	if err = js.Drain(ctxDrain); err != nil {
+		log.Println("[DRAIN][ERROR] jobs are not finished:", err)
+	}
+	cancelDrain()
+	js.StopAll()
+	stopHeartbeat()
+	if err = tasker.RemoveNode(cfg); err != nil {
+		log.Println("[SERVICE][ERROR] remove node:", err)
+	}
 
 	ctxClose, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
+	if grpcSrv != nil {
+		if err := grpcSrv.Stop(ctxClose); err != nil {
+			log.Println("[GRPC SERVER][ERROR] error while stopping: ", err)
+		}
+	}
 	err = srv.Stop(ctxClose)
 	if err != nil {
 		log.Fatalln("[SERVER][ERROR] error while stopping: ", err)
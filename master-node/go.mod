@@ -2,13 +2,28 @@ module master-node
 
 go 1.23
 
+require (
+	github.com/google/uuid v1.6.0
+	github.com/joho/godotenv v1.5.1
+	github.com/kelseyhightower/envconfig v1.4.0
+	github.com/valyala/fasthttp v1.59.0
+	go.starlark.net v0.0.0-20250225190231-0d3f41d403af
+	google.golang.org/grpc v1.70.0
+)
+
+require (
+	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20241202173237-19429a94021a // indirect
+	google.golang.org/protobuf v1.36.12 // indirect
+)
+
 require (
 	github.com/andybalholm/brotli v1.1.1 // indirect
-	github.com/google/uuid v1.6.0 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
-	github.com/kelseyhightower/envconfig v1.4.0 // indirect
 	github.com/klauspost/compress v1.17.11 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
-	github.com/valyala/fasthttp v1.59.0 // indirect
-	go.starlark.net v0.0.0-20250225190231-0d3f41d403af // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	protocol v0.0.0
 )
+
+replace protocol => ../protocol
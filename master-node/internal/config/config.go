@@ -5,24 +5,48 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 	"log"
+	"protocol"
 	"time"
 )
 
+// Version - версия мастера в heartbeat, задается при сборке: -ldflags "-X master-node/internal/config.Version=..."
+var Version = "dev"
+
 type Config struct {
 	UUID        string
 	PublicPort  string `envconfig:"PUBLIC_PORT" required:"true"`
 	PrivatePort string `envconfig:"PRIVATE_PORT" required:"true"`
+	GrpcPort    string `envconfig:"GRPC_PORT"` // порт gRPC сервиса для коллбеков менеджера, пусто - менеджер шлет их по REST
+	// адрес, по которому менеджер обращается к ноде. Пусто - адрес интерфейса, через который идут запросы к менеджеру
+	AdvertiseAddr string `envconfig:"ADVERTISE_ADDR"`
+
+	ManagerURL string `envconfig:"MANAGER_URL" required:"true"` // пути API менеджера - из protocol
+
+	// транспорт к менеджеру: http - REST, grpc - gRPC по MANAGER_GRPC_ADDR
+	Transport       string `envconfig:"TRANSPORT" default:"http"`
+	ManagerGrpcAddr string `envconfig:"MANAGER_GRPC_ADDR"` // "host:port" gRPC сервиса менеджера
+
+	HeartbeatInterval time.Duration `envconfig:"HEARTBEAT_INTERVAL" default:"10s"` // как часто мастер сообщает менеджеру, что он жив
+	DrainTimeout      time.Duration `envconfig:"DRAIN_TIMEOUT" default:"5m"`       // сколько при остановке дорешиваются поставленные задачи
+
+	// скрипты и движок по умолчанию для задач, в которых они не указаны
+	TaskScriptComputePath  string `envconfig:"TASK_SCRIPT_COMPUTE_PATH"`
+	TaskFuncNameCompute    string `envconfig:"TASK_COMPUTE_FUNC_NAME_COMPUTE" default:"compute"`
+	TaskScriptGeneratePath string `envconfig:"TASK_SCRIPT_GENERATE_PATH"`
+	TaskFuncNameGenerate   string `envconfig:"TASK_COMPUTE_FUNC_NAME_GENERATE" default:"generate"`
+	TaskScriptReducePath   string `envconfig:"TASK_SCRIPT_REDUCE_PATH"`
+	TaskFuncNameReduce     string `envconfig:"TASK_REDUCE_FUNC_NAME" default:"reduce"`
+	TaskReduceMaxSteps     uint64 `envconfig:"TASK_REDUCE_MAX_STEPS" default:"100000000"` // лимит шагов Starlark на один вызов reduce
+	TaskScriptDir          string `envconfig:"TASK_SCRIPT_DIR" default:"./script"`        // каталог скриптов, на которые задача может сослаться по пути
+	TaskEngine             string `envconfig:"TASK_ENGINE" default:"tsp"`
+	ResultSinkDir          string `envconfig:"RESULT_SINK_DIR" default:"./results"` // каталог результатов движка jsonl
+	TaskDataPath           string `envconfig:"TASK_DATA_PATH"`                      // если задан, при старте ставится задача с этими данными
 
-	ManagerURL        string `envconfig:"MANAGER_URL" required:"true"`
-	ManagerRegPath    string `envconfig:"MANAGER_REG_PATH" required:"true"`
-	ManagerAddPath    string `envconfig:"MANAGER_TASK_ADD" required:"true"`
-	ManagerClosePath  string `envconfig:"MANAGER_TASK_CLOSE" required:"true"`
-	ManagerStatusPath string `envconfig:"MANAGER_TASK_STATUS" required:"true"`
+	JobTTL time.Duration `envconfig:"JOB_TTL" default:"24h"` // сколько хранится завершенная задача с результатом, 0 - не удалять
 
-	TaskScriptComputePath  string `envconfig:"TASK_SCRIPT_COMPUTE_PATH" required:"true"`
-	TaskFuncNameCompute    string `envconfig:"TASK_COMPUTE_FUNC_NAME_COMPUTE" required:"true"`
-	TaskScriptGeneratePath string `envconfig:"TASK_SCRIPT_GENERATE_PATH" required:"true"`
-	TaskFuncNameGenerate   string `envconfig:"TASK_COMPUTE_FUNC_NAME_GENERATE" required:"true"`
+	TaskMaxExecutionSteps uint64        `envconfig:"TASK_MAX_EXECUTION_STEPS"` // лимиты выполнения на слейвах, 0 - по умолчанию слейва
+	TaskTimeout           time.Duration `envconfig:"TASK_TIMEOUT"`
+	TaskMaxResultBytes    int           `envconfig:"TASK_MAX_RESULT_BYTES"`
 
 	CheckHealthInterval time.Duration `envconfig:"HEALTH_CHECK_INTERVAL" required:"true"`
 }
@@ -43,7 +67,21 @@ func LoadConfig() *Config {
 		log.Fatalln("[CONFIG][ERROR]:", err)
 	}
 
+	switch cfg.Transport {
+	case protocol.TRANSPORT_HTTP:
+	case protocol.TRANSPORT_GRPC:
+		if cfg.ManagerGrpcAddr == "" {
+			log.Fatalln("[CONFIG][ERROR]: MANAGER_GRPC_ADDR is required for TRANSPORT=grpc")
+		}
+	default:
+		log.Fatalf("[CONFIG][ERROR]: unknown TRANSPORT %q\n", cfg.Transport)
+	}
+
 	cfg.UUID = uuid.NewString()
+	if cfg.AdvertiseAddr == "" {
+		// не определился - менеджер возьмет адрес, с которого пришла регистрация
+		cfg.AdvertiseAddr = protocol.OutboundAddr(cfg.ManagerURL)
+	}
 
 	cfg.PrintConfig()
 
@@ -54,13 +92,31 @@ func (c *Config) PrintConfig() {
 	log.Println("===================== CONFIG =====================")
 	log.Println("_____________SERVER____________ ")
 	log.Println("UUID................................. ", c.UUID)
+	log.Println("VERSION.............................. ", Version)
+	log.Println("HEARTBEAT_INTERVAL................... ", c.HeartbeatInterval)
+	log.Println("DRAIN_TIMEOUT........................ ", c.DrainTimeout)
 	log.Println("PUBLIC_PORT.......................... ", c.PublicPort)
 	log.Println("PRIVATE_PORT......................... ", c.PrivatePort)
+	log.Println("GRPC_PORT............................ ", c.GrpcPort)
+	log.Println("ADVERTISE_ADDR....................... ", c.AdvertiseAddr)
+	log.Println("TRANSPORT............................ ", c.Transport)
+	log.Println("MANAGER_GRPC_ADDR.................... ", c.ManagerGrpcAddr)
 	log.Println("_____________TASK____________ ")
 	log.Println("TASK_SCRIPT_COMPUTE_PATH............. ", c.TaskScriptComputePath)
 	log.Println("TASK_COMPUTE_FUNC_NAME_COMPUTE....... ", c.TaskFuncNameCompute)
 	log.Println("TASK_SCRIPT_GENERATE_PATH............ ", c.TaskScriptGeneratePath)
 	log.Println("TASK_COMPUTE_FUNC_NAME_GENERATE...... ", c.TaskFuncNameGenerate)
+	log.Println("TASK_SCRIPT_REDUCE_PATH.............. ", c.TaskScriptReducePath)
+	log.Println("TASK_REDUCE_FUNC_NAME................ ", c.TaskFuncNameReduce)
+	log.Println("TASK_REDUCE_MAX_STEPS................ ", c.TaskReduceMaxSteps)
+	log.Println("TASK_SCRIPT_DIR...................... ", c.TaskScriptDir)
+	log.Println("TASK_ENGINE.......................... ", c.TaskEngine)
+	log.Println("RESULT_SINK_DIR...................... ", c.ResultSinkDir)
+	log.Println("TASK_DATA_PATH....................... ", c.TaskDataPath)
+	log.Println("JOB_TTL.............................. ", c.JobTTL)
+	log.Println("TASK_MAX_EXECUTION_STEPS............. ", c.TaskMaxExecutionSteps)
+	log.Println("TASK_TIMEOUT......................... ", c.TaskTimeout)
+	log.Println("TASK_MAX_RESULT_BYTES................ ", c.TaskMaxResultBytes)
 
 	log.Println("==================================================")
 }
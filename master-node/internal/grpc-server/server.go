@@ -0,0 +1,113 @@
+package grpc_server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"master-node/internal/config"
+	"master-node/internal/jobs"
+	"master-node/internal/tasker"
+	"net"
+	"protocol/gridpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server - gRPC сервис мастера (GRPC_PORT) для коллбеков менеджера. REST коллбеки остаются доступными
+type Server struct {
+	gridpb.UnimplementedMasterServer
+
+	GrpcServer *grpc.Server
+	Cfg        *config.Config
+	jobs       *jobs.Jobs
+}
+
+func New(cfg *config.Config, js *jobs.Jobs) *Server {
+	s := &Server{
+		GrpcServer: grpc.NewServer(
+			grpc.MaxRecvMsgSize(gridpb.MAX_MESSAGE_SIZE),
+			grpc.MaxSendMsgSize(gridpb.MAX_MESSAGE_SIZE),
+		),
+		Cfg:  cfg,
+		jobs: js,
+	}
+	gridpb.RegisterMasterServer(s.GrpcServer, s)
+
+	return s
+}
+
+func (s *Server) Start() {
+	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0%s", s.Cfg.GrpcPort))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		if err := s.GrpcServer.Serve(lis); err != nil {
+			log.Fatal(err)
+		}
+	}()
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.GrpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.GrpcServer.Stop()
+	}
+	log.Println("[SERVER][STOP] gRPC server stopped")
+
+	return nil
+}
+
+// taskByUUID - воркер задачи по ее uuid на менеджере
+func (s *Server) taskByUUID(uuid string) (*tasker.Tasker, error) {
+	if uuid == "" {
+		return nil, status.Error(codes.InvalidArgument, "task uuid is required")
+	}
+	job, err := s.jobs.ByTaskUUID(uuid)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return job.Tasker(), nil
+}
+
+func (s *Server) SubtaskDone(ctx context.Context, req *gridpb.SubtaskResult) (*gridpb.Empty, error) {
+	t, err := s.taskByUUID(req.GetTaskUuid())
+	if err != nil {
+		return nil, err
+	}
+	t.AddSubtask(req.Protocol())
+
+	return &gridpb.Empty{}, nil
+}
+
+func (s *Server) TaskDone(ctx context.Context, req *gridpb.TaskSummary) (*gridpb.Empty, error) {
+	t, err := s.taskByUUID(req.GetTaskUuid())
+	if err != nil {
+		return nil, err
+	}
+	t.DoneTask(req.Protocol())
+
+	return &gridpb.Empty{}, nil
+}
+
+func (s *Server) TaskError(ctx context.Context, req *gridpb.TaskError) (*gridpb.Empty, error) {
+	t, err := s.taskByUUID(req.GetTaskUuid())
+	if err != nil {
+		return nil, err
+	}
+	t.ErrorTask(errors.New(req.GetError()))
+
+	return &gridpb.Empty{}, nil
+}
@@ -0,0 +1,418 @@
+package jobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"master-node/internal/config"
+	"master-node/internal/reduce"
+	"master-node/internal/sink"
+	"master-node/internal/tasker"
+	tasker_impl "master-node/internal/tasker-impl"
+	"master-node/pkg/model"
+	"path/filepath"
+	"protocol"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+// engineFactory - создание движка задачи по запросу
+type engineFactory func(js *Jobs, jobID string, req model.JobRequest, task protocol.TaskConfig) (tasker.TaskEngine, error)
+
+// engines - движки обработки результатов подзадач, выбираются по имени в JobRequest.Engine
+var engines = map[string]engineFactory{
+	"tsp": func(js *Jobs, jobID string, req model.JobRequest, task protocol.TaskConfig) (tasker.TaskEngine, error) {
+		return tasker_impl.New(js.cfg), nil
+	},
+	"mock": func(js *Jobs, jobID string, req model.JobRequest, task protocol.TaskConfig) (tasker.TaskEngine, error) {
+		return tasker.TaskEngineMock{}, nil
+	},
+	"reduce": newReduceEngine,
+	"jsonl":  newSinkEngine,
+}
+
+/*
+newSinkEngine - результаты подзадач дописываются в JSONL файл (или каталог) задачи в каталоге результатов мастера
+
+Путь по умолчанию - ключ по скриптам и данным задачи, поэтому повторная постановка той же задачи,
+например после падения мастера, продолжает запись в тот же файл
+*/
+func newSinkEngine(js *Jobs, jobID string, req model.JobRequest, task protocol.TaskConfig) (tasker.TaskEngine, error) {
+	path := req.Sink.Path
+	if path == "" {
+		path = sinkKey(task)
+		if !req.Sink.Dir {
+			path += ".jsonl"
+		}
+	}
+
+	full, err := insideDir(js.cfg.ResultSinkDir, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return sink.Open(full, req.Sink.Dir)
+}
+
+// sinkKey - имя результатов задачи по умолчанию: sha256 скриптов и входных данных
+func sinkKey(task protocol.TaskConfig) string {
+	data, _ := json.Marshal(struct {
+		Generator protocol.ScriptConfig
+		Compute   protocol.ScriptConfig
+		Data      json.RawMessage
+	}{task.GeneratorScript, task.ComputeScript, task.Data})
+	sum := sha256.Sum256(data)
+
+	return "job-" + hex.EncodeToString(sum[:8])
+}
+
+func init() {
+	for _, name := range reduce.Builtins {
+		name := name
+		engines[name] = func(js *Jobs, jobID string, req model.JobRequest, task protocol.TaskConfig) (tasker.TaskEngine, error) {
+			return reduce.NewBuiltin(name, req.Reducer)
+		}
+	}
+}
+
+// newReduceEngine - результаты подзадач сворачиваются Starlark функцией reduce(acc, partial) из ReduceScript
+func newReduceEngine(js *Jobs, jobID string, req model.JobRequest, task protocol.TaskConfig) (tasker.TaskEngine, error) {
+	script, err := js.loadScript(req.ReduceScript, js.cfg.TaskScriptReducePath, js.cfg.TaskFuncNameReduce)
+	if err != nil {
+		return nil, fmt.Errorf("reduce script: %v", err)
+	}
+	if err = tasker.CheckScript(script); err != nil {
+		return nil, fmt.Errorf("reduce script: %v", err)
+	}
+
+	return reduce.New(script, js.cfg.TaskReduceMaxSteps)
+}
+
+// Job - задача мастера. У каждой задачи свой Tasker
+type Job struct {
+	ID        string
+	Name      string
+	Engine    string
+	CreatedAt time.Time
+
+	t          *tasker.Tasker
+	finishedAt time.Time // когда воркер очистки увидел задачу завершенной, под Jobs.mu
+}
+
+func (j *Job) Tasker() *tasker.Tasker {
+	return j.t
+}
+
+func (j *Job) Info() model.JobInfo {
+	info := model.JobInfo{
+		JobID:     j.ID,
+		Name:      j.Name,
+		Engine:    j.Engine,
+		TaskUUID:  j.t.GetTaskUUID(),
+		Status:    j.t.GetStatus(),
+		CreatedAt: j.CreatedAt,
+		Coverage:  j.t.GetCoverage(),
+		Summary:   j.t.GetSummary(),
+	}
+	if err := j.t.GetError(); err != nil {
+		info.Error = err.Error()
+	}
+
+	return info
+}
+
+// Jobs - реестр задач мастера
+type Jobs struct {
+	cfg *config.Config
+
+	jobs   map[string]*Job // ключ - id задачи на мастере
+	byTask map[string]*Job // ключ - uuid задачи на менеджере, по нему приходят коллбеки
+
+	starting int        // задачи, которые сейчас отправляются менеджеру: их uuid еще нет в byTask
+	started  *sync.Cond // отправка задачи менеджеру закончилась
+	draining bool       // мастер останавливается, новые задачи не принимаются
+
+	mu sync.Mutex
+}
+
+func New(cfg *config.Config) *Jobs {
+	js := &Jobs{
+		cfg:    cfg,
+		jobs:   make(map[string]*Job),
+		byTask: make(map[string]*Job),
+	}
+	js.started = sync.NewCond(&js.mu)
+
+	if cfg.JobTTL > 0 {
+		go js.retentionWorker() // воркер удаления старых завершенных задач
+	}
+
+	return js
+}
+
+// Submit - создание задачи и отправка ее менеджеру
+func (js *Jobs) Submit(req model.JobRequest) (*Job, error) {
+	engineName := req.Engine
+	if engineName == "" {
+		engineName = js.cfg.TaskEngine
+	}
+	newEngine, ok := engines[engineName]
+	if !ok {
+		return nil, fmt.Errorf("unknown engine %q", engineName)
+	}
+
+	generatorScript, err := js.loadScript(req.GeneratorScript, js.cfg.TaskScriptGeneratePath, js.cfg.TaskFuncNameGenerate)
+	if err != nil {
+		return nil, fmt.Errorf("generator script: %v", err)
+	}
+	computeScript, err := js.loadScript(req.ComputeScript, js.cfg.TaskScriptComputePath, js.cfg.TaskFuncNameCompute)
+	if err != nil {
+		return nil, fmt.Errorf("compute script: %v", err)
+	}
+	if len(req.Data) == 0 {
+		return nil, errors.New("job data is required")
+	}
+
+	limits := req.Limits
+	if limits == (protocol.ExecutionLimits{}) {
+		limits = protocol.ExecutionLimits{
+			MaxSteps:       js.cfg.TaskMaxExecutionSteps,
+			TimeoutMs:      js.cfg.TaskTimeout.Milliseconds(),
+			MaxResultBytes: js.cfg.TaskMaxResultBytes,
+		}
+	}
+
+	task := protocol.TaskConfig{
+		GeneratorScript: generatorScript,
+		ComputeScript:   computeScript,
+		Data:            req.Data,
+		Limits:          limits,
+	}
+
+	jobID := uuid.NewString()
+	engine, err := newEngine(js, jobID, req, task)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := tasker.NewTasker(context.Background(), js.cfg, engine, task)
+	if err != nil {
+		tasker.CloseEngine(engine)
+		return nil, err
+	}
+
+	job := &Job{
+		ID:        jobID,
+		Name:      req.Name,
+		Engine:    engineName,
+		CreatedAt: time.Now(),
+		t:         t,
+	}
+
+	// задача регистрируется до отправки менеджеру, а лок на время отправки не держится.
+	// Коллбеки менеджера могут прийти раньше, чем uuid задачи попадет в byTask, их дожидается ByTaskUUID
+	js.mu.Lock()
+	if js.draining {
+		js.mu.Unlock()
+		tasker.CloseEngine(engine)
+		return nil, protocol.ErrDraining
+	}
+	js.jobs[job.ID] = job
+	js.starting++
+	js.mu.Unlock()
+
+	err = t.Start()
+
+	js.mu.Lock()
+	js.starting--
+	if err != nil {
+		delete(js.jobs, job.ID)
+	} else {
+		js.byTask[t.GetTaskUUID()] = job
+	}
+	js.started.Broadcast()
+	js.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[JOB] %s (%s) submitted, engine: %s, task: %s\n", job.ID, job.Name, job.Engine, t.GetTaskUUID())
+
+	return job, nil
+}
+
+/*
+loadScript - скрипт задачи из запроса: код целиком или файл из каталога скриптов.
+Если в запросе скрипта нет, берется скрипт из конфига мастера
+*/
+func (js *Jobs) loadScript(s model.JobScript, defaultPath string, defaultFunc string) (protocol.ScriptConfig, error) {
+	funcName := s.FuncName
+	if funcName == "" {
+		funcName = defaultFunc
+	}
+
+	switch {
+	case s.Script != "":
+		return protocol.ScriptConfig{Script: s.Script, FuncName: funcName}, nil
+	case s.Path != "":
+		path, err := js.scriptPath(s.Path)
+		if err != nil {
+			return protocol.ScriptConfig{}, err
+		}
+		return tasker.LoadScript(path, funcName)
+	case defaultPath != "":
+		return tasker.LoadScript(defaultPath, funcName)
+	default:
+		return protocol.ScriptConfig{}, errors.New("script or path is required")
+	}
+}
+
+// scriptPath - путь к скрипту внутри каталога скриптов мастера
+func (js *Jobs) scriptPath(path string) (string, error) {
+	return insideDir(js.cfg.TaskScriptDir, path)
+}
+
+// insideDir - путь внутри каталога, выход за его пределы запрещен
+func insideDir(dir string, path string) (string, error) {
+	dir = filepath.Clean(dir)
+	full := filepath.Join(dir, path)
+	if full == dir || !strings.HasPrefix(full, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside of %s", path, dir)
+	}
+
+	return full, nil
+}
+
+func (js *Jobs) Get(id string) (*Job, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	job, ok := js.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	return job, nil
+}
+
+// ByTaskUUID - задача по uuid задачи на менеджере. Пока какие-то задачи отправляются менеджеру, незнакомый uuid может быть одной из них
+func (js *Jobs) ByTaskUUID(taskUuid string) (*Job, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	for {
+		if job, ok := js.byTask[taskUuid]; ok {
+			return job, nil
+		}
+		if js.starting == 0 {
+			return nil, ErrJobNotFound
+		}
+		js.started.Wait()
+	}
+}
+
+// Heartbeat - состояние мастера для heartbeat менеджеру
+func (js *Jobs) Heartbeat() protocol.Heartbeat {
+	js.mu.Lock()
+	jobs := make([]*Job, 0, len(js.jobs))
+	for _, job := range js.jobs {
+		jobs = append(jobs, job)
+	}
+	js.mu.Unlock()
+
+	solving := 0
+	for _, job := range jobs {
+		if job.t.GetStatus() == "solving" {
+			solving++
+		}
+	}
+
+	return protocol.Heartbeat{
+		UUID:    js.cfg.UUID,
+		Kind:    protocol.NODE_KIND_MASTER,
+		Status:  "ok",
+		Load:    solving,
+		Version: config.Version,
+	}
+}
+
+// List - все задачи мастера, от старых к новым
+func (js *Jobs) List() []model.JobInfo {
+	js.mu.Lock()
+	jobs := make([]*Job, 0, len(js.jobs))
+	for _, job := range js.jobs {
+		jobs = append(jobs, job)
+	}
+	js.mu.Unlock()
+
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].CreatedAt.Before(jobs[k].CreatedAt) })
+
+	infos := make([]model.JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		infos = append(infos, job.Info())
+	}
+
+	return infos
+}
+
+// Cancel - отмена задачи на менеджере
+func (js *Jobs) Cancel(id string) error {
+	job, err := js.Get(id)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[JOB] %s cancel\n", id)
+
+	return job.t.Stop()
+}
+
+/*
+Drain - остановка мастера: новые задачи не принимаются, поставленные дорешиваются, пока не истечет ctx
+
+Коллбеки менеджера в это время принимаются как обычно. Задачи, не успевшие завершиться, отменяет StopAll
+*/
+func (js *Jobs) Drain(ctx context.Context) error {
+	js.mu.Lock()
+	js.draining = true
+	jobs := make([]*Job, 0, len(js.jobs))
+	for _, job := range js.jobs {
+		jobs = append(jobs, job)
+	}
+	js.mu.Unlock()
+
+	for _, job := range jobs {
+		select {
+		case <-job.t.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// StopAll - отмена всех решаемых задач при остановке мастера
+func (js *Jobs) StopAll() {
+	js.mu.Lock()
+	jobs := make([]*Job, 0, len(js.jobs))
+	for _, job := range js.jobs {
+		jobs = append(jobs, job)
+	}
+	js.mu.Unlock()
+
+	for _, job := range jobs {
+		if err := job.t.Stop(); err != nil {
+			log.Printf("[JOB][ERROR] stop %s: %v\n", job.ID, err)
+		}
+	}
+}
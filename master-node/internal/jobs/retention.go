@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+/*
+retentionWorker - удаление задач, завершенных, упавших или отмененных раньше 'JOB_TTL'
+
+Вместе с задачей освобождаются ее Tasker, покрытие и состояние движка. Время завершения
+отмечается при первом обходе, на котором задача уже завершена
+*/
+func (js *Jobs) retentionWorker() {
+	interval := js.cfg.JobTTL / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+
+		js.mu.Lock()
+		for id, job := range js.jobs {
+			if job.finishedAt.IsZero() {
+				select {
+				case <-job.t.Done():
+					job.finishedAt = now
+				default:
+				}
+				continue
+			}
+			if now.Sub(job.finishedAt) < js.cfg.JobTTL {
+				continue
+			}
+			delete(js.jobs, id)
+			if taskUuid := job.t.GetTaskUUID(); taskUuid != "" && js.byTask[taskUuid] == job {
+				delete(js.byTask, taskUuid)
+			}
+			log.Printf("[JOB] %s expired, finished %s\n", id, job.finishedAt.Format(time.RFC3339))
+		}
+		js.mu.Unlock()
+	}
+}
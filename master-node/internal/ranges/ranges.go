@@ -0,0 +1,50 @@
+// Package ranges - учет покрытых диапазонов позиций [start, end), по которым мастер отбрасывает повторы результатов
+package ranges
+
+import (
+	"master-node/pkg/model"
+	"sort"
+)
+
+// Set - множество диапазонов, хранятся отсортированными и без пересечений
+type Set struct {
+	ranges []model.Range
+}
+
+// Covered - диапазон целиком внутри одного из добавленных
+func (s *Set) Covered(start, end uint64) bool {
+	i := sort.Search(len(s.ranges), func(i int) bool { return s.ranges[i].End > start })
+
+	return i < len(s.ranges) && s.ranges[i].Start <= start && end <= s.ranges[i].End
+}
+
+// Overlaps - диапазон пересекается хотя бы с одним из добавленных
+func (s *Set) Overlaps(start, end uint64) bool {
+	i := sort.Search(len(s.ranges), func(i int) bool { return s.ranges[i].End > start })
+
+	return i < len(s.ranges) && s.ranges[i].Start < end
+}
+
+// Add - добавление диапазона со слиянием соседних и пересекающихся
+func (s *Set) Add(start, end uint64) {
+	i := sort.Search(len(s.ranges), func(i int) bool { return s.ranges[i].End >= start })
+	j := i
+	for j < len(s.ranges) && s.ranges[j].Start <= end {
+		if s.ranges[j].Start < start {
+			start = s.ranges[j].Start
+		}
+		if s.ranges[j].End > end {
+			end = s.ranges[j].End
+		}
+		j++
+	}
+
+	merged := append([]model.Range{}, s.ranges[:i]...)
+	merged = append(merged, model.Range{Start: start, End: end})
+	s.ranges = append(merged, s.ranges[j:]...)
+}
+
+// Ranges - копия диапазонов по возрастанию
+func (s *Set) Ranges() []model.Range {
+	return append([]model.Range{}, s.ranges...)
+}
@@ -0,0 +1,357 @@
+package reduce
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"master-node/pkg/model"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// встроенные движки свертки, выбираются по имени в JobRequest.Engine
+const (
+	MIN_BY    = "min"       // результат с минимальным значением по ключу
+	MAX_BY    = "max"       // результат с максимальным значением по ключу
+	SUM       = "sum"       // сумма значений по ключу
+	COUNT     = "count"     // кол-во результатов (с непустым значением по ключу, если он задан)
+	CONCAT    = "concat"    // значения по ключу в один список, массивы разворачиваются
+	TOP_K     = "topk"      // K результатов с наибольшими (или наименьшими) значениями по ключу
+	HISTOGRAM = "histogram" // слияние гистограмм {корзина: кол-во} по ключу
+)
+
+var Builtins = []string{MIN_BY, MAX_BY, SUM, COUNT, CONCAT, TOP_K, HISTOGRAM}
+
+// folder - свертка одного результата подзадачи
+type folder interface {
+	fold(partial interface{}) error
+	result() interface{}
+}
+
+/*
+Builtin - движок задачи со встроенной сверткой результатов подзадач.
+
+Результат подзадачи разбирается как JSON, значение для свертки берется по пути ключа (ReducerOptions.Key),
+например "data.cost" или "routes.0". Пустой ключ - весь результат
+*/
+type Builtin struct {
+	name string
+	f    folder
+
+	count  int // кол-во свернутых результатов
+	failed int // кол-во результатов, которые не удалось свернуть
+}
+
+func NewBuiltin(name string, opts model.ReducerOptions) (*Builtin, error) {
+	key := splitKey(opts.Key)
+
+	var f folder
+	switch name {
+	case MIN_BY:
+		f = &extremum{key: key, less: true}
+	case MAX_BY:
+		f = &extremum{key: key}
+	case SUM:
+		f = &sum{key: key, isInt: true}
+	case COUNT:
+		f = &count{key: key}
+	case CONCAT:
+		f = &concat{key: key, items: []interface{}{}}
+	case TOP_K:
+		if opts.K <= 0 {
+			return nil, fmt.Errorf("%s reducer requires K > 0", name)
+		}
+		f = &topK{key: key, k: opts.K, ascending: opts.Ascending}
+	case HISTOGRAM:
+		f = &histogram{key: key, buckets: make(map[string]*sum)}
+	default:
+		return nil, fmt.Errorf("unknown reducer %q", name)
+	}
+
+	return &Builtin{name: name, f: f}, nil
+}
+
+func (b *Builtin) ConfirmSubtaskHandler(message json.RawMessage) {
+	dec := json.NewDecoder(bytes.NewReader(message))
+	dec.UseNumber()
+
+	var partial interface{}
+	if err := dec.Decode(&partial); err != nil {
+		b.fail(fmt.Errorf("decode partial: %v", err))
+		return
+	}
+	if err := b.f.fold(partial); err != nil {
+		b.fail(err)
+		return
+	}
+	b.count++
+}
+
+func (b *Builtin) fail(err error) {
+	b.failed++
+	log.Printf("[REDUCE][%s][ERROR] %v\n", b.name, err)
+}
+
+func (b *Builtin) DoneTaskHandler() {
+	log.Printf("[REDUCE][%s][DONE] reduced: %d, failed: %d\n", b.name, b.count, b.failed)
+}
+
+func (b *Builtin) ErrorTaskHandler(err error) {
+	log.Printf("[REDUCE][%s] Error: %v\n", b.name, err)
+}
+
+func (b *Builtin) Result() interface{} {
+	return b.f.result()
+}
+
+func splitKey(key string) []string {
+	if key == "" {
+		return nil
+	}
+	return strings.Split(key, ".")
+}
+
+// lookup - значение по пути ключа, ok=false если пути нет
+func lookup(v interface{}, key []string) (interface{}, bool) {
+	for _, part := range key {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, v != nil
+}
+
+// number - числовое значение по пути ключа
+func number(v interface{}, key []string) (json.Number, float64, error) {
+	val, ok := lookup(v, key)
+	if !ok {
+		return "", 0, fmt.Errorf("key %q not found", strings.Join(key, "."))
+	}
+	n, ok := val.(json.Number)
+	if !ok {
+		return "", 0, fmt.Errorf("key %q is not a number: %v", strings.Join(key, "."), val)
+	}
+	f, err := n.Float64()
+
+	return n, f, err
+}
+
+// extremum - min/max по ключу, хранится весь результат подзадачи
+type extremum struct {
+	key  []string
+	less bool
+
+	best  interface{}
+	value float64
+	set   bool
+}
+
+func (e *extremum) fold(partial interface{}) error {
+	_, f, err := number(partial, e.key)
+	if err != nil {
+		return err
+	}
+	if !e.set || (e.less && f < e.value) || (!e.less && f > e.value) {
+		e.best, e.value, e.set = partial, f, true
+	}
+
+	return nil
+}
+
+func (e *extremum) result() interface{} {
+	return e.best
+}
+
+// sum - сумма по ключу. Пока все слагаемые целые и сумма не переполняет int64, она считается в int64
+type sum struct {
+	key []string
+
+	isInt bool
+	i     int64
+	f     float64
+}
+
+func (s *sum) fold(partial interface{}) error {
+	n, f, err := number(partial, s.key)
+	if err != nil {
+		return err
+	}
+	s.add(n, f)
+
+	return nil
+}
+
+// add - слагаемое. При переполнении int64 сумма продолжается в float64, она считается параллельно с самого начала
+func (s *sum) add(n json.Number, f float64) {
+	if s.isInt {
+		i, err := n.Int64()
+		r := s.i + i
+		if err == nil && (i >= 0) == (r >= s.i) {
+			s.i = r
+			s.f += f
+			return
+		}
+		s.isInt = false
+	}
+	s.f += f
+}
+
+func (s *sum) result() interface{} {
+	if s.isInt {
+		return s.i
+	}
+	return s.f
+}
+
+type count struct {
+	key []string
+	n   int
+}
+
+func (c *count) fold(partial interface{}) error {
+	if _, ok := lookup(partial, c.key); ok {
+		c.n++
+	}
+	return nil
+}
+
+func (c *count) result() interface{} {
+	return c.n
+}
+
+type concat struct {
+	key   []string
+	items []interface{}
+}
+
+func (c *concat) fold(partial interface{}) error {
+	val, ok := lookup(partial, c.key)
+	if !ok {
+		return nil
+	}
+	if list, ok := val.([]interface{}); ok {
+		c.items = append(c.items, list...)
+		return nil
+	}
+	c.items = append(c.items, val)
+
+	return nil
+}
+
+func (c *concat) result() interface{} {
+	return c.items
+}
+
+// topK - K результатов подзадач, отсортированных по ключу. Новый результат вставляется на свое место, список не длиннее K
+type topK struct {
+	key       []string
+	k         int
+	ascending bool // true - K наименьших
+
+	items []topItem
+}
+
+type topItem struct {
+	value   float64
+	partial interface{}
+}
+
+func (t *topK) fold(partial interface{}) error {
+	_, f, err := number(partial, t.key)
+	if err != nil {
+		return err
+	}
+
+	// место после равных значений: из равных остаются пришедшие раньше
+	i := sort.Search(len(t.items), func(i int) bool {
+		if t.ascending {
+			return t.items[i].value > f
+		}
+		return t.items[i].value < f
+	})
+	if i >= t.k {
+		return nil
+	}
+
+	if len(t.items) < t.k {
+		t.items = append(t.items, topItem{})
+	}
+	copy(t.items[i+1:], t.items[i:])
+	t.items[i] = topItem{value: f, partial: partial}
+
+	return nil
+}
+
+func (t *topK) result() interface{} {
+	res := make([]interface{}, 0, len(t.items))
+	for _, item := range t.items {
+		res = append(res, item.partial)
+	}
+	return res
+}
+
+// histogram - слияние гистограмм: по ключу объект {корзина: кол-во}, кол-ва складываются.
+// Гистограмма с нечисловой корзиной отбрасывается целиком, частично она не сливается
+type histogram struct {
+	key     []string
+	buckets map[string]*sum
+}
+
+func (h *histogram) fold(partial interface{}) error {
+	val, ok := lookup(partial, h.key)
+	if !ok {
+		return fmt.Errorf("key %q not found", strings.Join(h.key, "."))
+	}
+	hist, ok := val.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("key %q is not a histogram object", strings.Join(h.key, "."))
+	}
+
+	type bucketCount struct {
+		n json.Number
+		f float64
+	}
+	counts := make(map[string]bucketCount, len(hist))
+	for bucket, v := range hist {
+		n, f, err := number(v, nil)
+		if err != nil {
+			return fmt.Errorf("bucket %q: %v", bucket, err)
+		}
+		counts[bucket] = bucketCount{n: n, f: f}
+	}
+
+	for bucket, c := range counts {
+		s, ok := h.buckets[bucket]
+		if !ok {
+			s = &sum{isInt: true}
+			h.buckets[bucket] = s
+		}
+		s.add(c.n, c.f)
+	}
+
+	return nil
+}
+
+func (h *histogram) result() interface{} {
+	res := make(map[string]interface{}, len(h.buckets))
+	for bucket, s := range h.buckets {
+		res[bucket] = s.result()
+	}
+	return res
+}
@@ -0,0 +1,105 @@
+package reduce
+
+import (
+	"encoding/json"
+	"fmt"
+	starlarkjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"log"
+	"protocol"
+)
+
+/*
+Reducer - движок задачи, который сворачивает результаты подзадач пользовательской Starlark функцией
+
+	def reduce(acc, partial):
+	    ...
+	    return acc
+
+acc в первом вызове - None, partial - результат подзадачи (JSON, переведенный в Starlark значения).
+Итоговый аккумулятор отдается в Result
+*/
+type Reducer struct {
+	fn       starlark.Callable
+	maxSteps uint64
+
+	acc    starlark.Value
+	count  int // кол-во свернутых результатов
+	failed int // кол-во результатов, на которых reduce вернул ошибку
+
+	decode starlark.Value // json.decode из Starlark: числа без точки остаются int
+	encode starlark.Value
+}
+
+func New(script protocol.ScriptConfig, maxSteps uint64) (*Reducer, error) {
+	globals, err := starlark.ExecFile(newThread(maxSteps), "reduce.star", script.Script, nil)
+	if err != nil {
+		return nil, fmt.Errorf("reduce script error: %v", err)
+	}
+	fn, ok := globals[script.FuncName].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("reduce script does not contain function %s", script.FuncName)
+	}
+
+	return &Reducer{
+		fn:       fn,
+		maxSteps: maxSteps,
+		acc:      starlark.None,
+		decode:   starlarkjson.Module.Members["decode"],
+		encode:   starlarkjson.Module.Members["encode"],
+	}, nil
+}
+
+// newThread - поток на каждый вызов reduce, лимит шагов действует на один вызов
+func newThread(maxSteps uint64) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name:  "reduce",
+		Print: func(_ *starlark.Thread, msg string) { log.Println("[SCRIPT][REDUCE]", msg) },
+	}
+	if maxSteps > 0 {
+		thread.SetMaxExecutionSteps(maxSteps)
+	}
+
+	return thread
+}
+
+func (r *Reducer) ConfirmSubtaskHandler(message json.RawMessage) {
+	thread := newThread(r.maxSteps)
+
+	partial, err := starlark.Call(thread, r.decode, starlark.Tuple{starlark.String(message)}, nil)
+	if err != nil {
+		r.fail(fmt.Errorf("decode partial: %v", err))
+		return
+	}
+
+	acc, err := starlark.Call(thread, r.fn, starlark.Tuple{r.acc, partial}, nil)
+	if err != nil {
+		r.fail(fmt.Errorf("reduce: %v", err))
+		return
+	}
+	r.acc = acc
+	r.count++
+}
+
+func (r *Reducer) fail(err error) {
+	r.failed++
+	log.Println("[REDUCE][ERROR]", err)
+}
+
+func (r *Reducer) DoneTaskHandler() {
+	log.Printf("[REDUCE][DONE] reduced: %d, failed: %d, result: %s\n", r.count, r.failed, r.acc)
+}
+
+func (r *Reducer) ErrorTaskHandler(err error) {
+	log.Println("[REDUCE] Error:", err)
+}
+
+// Result - текущий аккумулятор в JSON
+func (r *Reducer) Result() interface{} {
+	encoded, err := starlark.Call(newThread(r.maxSteps), r.encode, starlark.Tuple{r.acc}, nil)
+	if err != nil {
+		return map[string]string{"error": fmt.Sprintf("encode accumulator: %v", err)}
+	}
+
+	return json.RawMessage(encoded.(starlark.String))
+}
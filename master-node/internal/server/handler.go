@@ -4,15 +4,45 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/valyala/fasthttp"
+	"master-node/internal/jobs"
+	"master-node/internal/tasker"
+	"master-node/pkg/model"
 	"net/http"
+	"protocol"
 )
 
+// taskByUUID - задача, по которой пришел коллбек менеджера (?uuid= - uuid задачи на менеджере)
+func (s *Server) taskByUUID(args *fasthttp.Args) (*tasker.Tasker, error) {
+	uuid := string(args.Peek("uuid"))
+	if uuid == "" {
+		return nil, errors.New("task uuid is required")
+	}
+	job, err := s.jobs.ByTaskUUID(uuid)
+	if err != nil {
+		return nil, errNotFound
+	}
+
+	return job.Tasker(), nil
+}
+
 func (s *Server) taskDone(method string, body []byte, args *fasthttp.Args) error {
-	if method != http.MethodGet {
+	if method != http.MethodGet && method != http.MethodPost {
 		return errMethodNotAllowed
 	}
+	t, err := s.taskByUUID(args)
+	if err != nil {
+		return err
+	}
+
+	// менеджер присылает итог задачи, старые версии - пустой GET
+	var summary protocol.TaskSummary
+	if len(body) != 0 {
+		if err = json.Unmarshal(body, &summary); err != nil {
+			return err
+		}
+	}
 
-	s.taskCli.DoneTask()
+	t.DoneTask(summary)
 
 	return nil
 }
@@ -21,14 +51,18 @@ func (s *Server) taskError(method string, body []byte, args *fasthttp.Args) erro
 	if method != http.MethodPost {
 		return errMethodNotAllowed
 	}
+	t, err := s.taskByUUID(args)
+	if err != nil {
+		return err
+	}
 
 	var errStr string
-	err := json.Unmarshal(body, &errStr)
+	err = json.Unmarshal(body, &errStr)
 	if err != nil {
 		return err
 	}
 
-	s.taskCli.ErrorTask(errors.New(errStr))
+	t.ErrorTask(errors.New(errStr))
 
 	return nil
 }
@@ -37,18 +71,113 @@ func (s *Server) subtaskDone(method string, body []byte, args *fasthttp.Args) er
 	if method != http.MethodPost {
 		return errMethodNotAllowed
 	}
+	t, err := s.taskByUUID(args)
+	if err != nil {
+		return err
+	}
 
-	var reqBody RequestSubtaskData
-	err := json.Unmarshal(body, &reqBody)
+	var res protocol.SubtaskResult
+	err = json.Unmarshal(body, &res)
 	if err != nil {
 		return err
 	}
 
-	s.taskCli.AddSubtask(reqBody.Data)
+	t.AddSubtask(res)
 
 	return nil
 }
 
-type RequestSubtaskData struct {
-	Data json.RawMessage `json:"Data"`
+// addJob - постановка задачи, в ответ отдается id задачи на мастере и uuid задачи на менеджере
+func (s *Server) addJob(method string, body []byte, args *fasthttp.Args) ([]byte, error) {
+	if method != http.MethodPost {
+		return nil, errMethodNotAllowed
+	}
+
+	var req model.JobRequest
+	err := json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.jobs.Submit(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(model.AddJobResp{JobID: job.ID, TaskUUID: job.Tasker().GetTaskUUID()})
+}
+
+// listJobs - все задачи мастера
+func (s *Server) listJobs(method string, body []byte, args *fasthttp.Args) ([]byte, error) {
+	if method != http.MethodGet {
+		return nil, errMethodNotAllowed
+	}
+
+	return json.Marshal(s.jobs.List())
+}
+
+// jobStatus - состояние задачи на мастере и на менеджере (?id=)
+func (s *Server) jobStatus(method string, body []byte, args *fasthttp.Args) ([]byte, error) {
+	if method != http.MethodGet {
+		return nil, errMethodNotAllowed
+	}
+
+	job, err := s.jobByID(args)
+	if err != nil {
+		return nil, err
+	}
+
+	status := model.JobStatus{JobInfo: job.Info()}
+	if managerStatus, err := job.Tasker().GetManagerStatus(); err == nil {
+		status.Manager = &managerStatus
+	}
+
+	return json.Marshal(status)
+}
+
+// jobResult - текущий результат задачи (?id=)
+func (s *Server) jobResult(method string, body []byte, args *fasthttp.Args) ([]byte, error) {
+	if method != http.MethodGet {
+		return nil, errMethodNotAllowed
+	}
+
+	job, err := s.jobByID(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(model.JobResult{JobInfo: job.Info(), Result: job.Tasker().GetResult()})
+}
+
+// cancelJob - отмена задачи (?id=)
+func (s *Server) cancelJob(method string, body []byte, args *fasthttp.Args) error {
+	if method != http.MethodPost {
+		return errMethodNotAllowed
+	}
+
+	id := string(args.Peek("id"))
+	if id == "" {
+		return errors.New("job id is required")
+	}
+
+	err := s.jobs.Cancel(id)
+	if err == jobs.ErrJobNotFound {
+		return errNotFound
+	}
+
+	return err
+}
+
+func (s *Server) jobByID(args *fasthttp.Args) (*jobs.Job, error) {
+	id := string(args.Peek("id"))
+	if id == "" {
+		return nil, errors.New("job id is required")
+	}
+
+	job, err := s.jobs.Get(id)
+	if err != nil {
+		return nil, errNotFound
+	}
+
+	return job, nil
 }
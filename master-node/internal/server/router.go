@@ -1,21 +1,30 @@
 package server
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/valyala/fasthttp"
 	"master-node/internal/utils"
+	"protocol"
 	"strings"
 	"unicode/utf8"
 )
 
 const (
-	// Nodes
-	SUBTASK_DONE = "/subtask/done"
+	// Nodes - вызываются менеджером, пути из общего протокола
+	SUBTASK_DONE = protocol.MASTER_SUBTASK_DONE_PATH
 
-	TASK_DONE  = "/task/done"
-	TASK_ERROR = "/task/error"
+	TASK_DONE  = protocol.MASTER_TASK_DONE_PATH
+	TASK_ERROR = protocol.MASTER_TASK_ERROR_PATH
 
-	V1 = "/api/v1"
+	// Jobs
+	JOB_ADD    = protocol.MASTER_JOB_ADD_PATH
+	JOB_LIST   = protocol.MASTER_JOB_LIST_PATH
+	JOB_STATUS = protocol.MASTER_JOB_STATUS_PATH
+	JOB_RESULT = protocol.MASTER_JOB_RESULT_PATH
+	JOB_CANCEL = protocol.MASTER_JOB_CANCEL_PATH
+
+	V1 = protocol.API_V1
 )
 
 var (
@@ -38,6 +47,18 @@ func (s *Server) Router(ctx *fasthttp.RequestCtx) {
 
 }
 
+// errorBody - текст ошибки, а для отклоненных скриптов - JSON с позицией ошибки
+func errorBody(err error) []byte {
+	var scriptErr *protocol.ScriptError
+	if errors.As(err, &scriptErr) {
+		if body, e := json.Marshal(scriptErr); e == nil {
+			return body
+		}
+	}
+
+	return []byte(err.Error())
+}
+
 func (s *Server) Handler(path string, ctx *fasthttp.RequestCtx) {
 
 	defer utils.Recovery("SERVER")
@@ -55,12 +76,23 @@ func (s *Server) Handler(path string, ctx *fasthttp.RequestCtx) {
 	case SUBTASK_DONE:
 		err = s.subtaskDone(method, body, ctx.QueryArgs())
 
+	case JOB_ADD:
+		resp, err = s.addJob(method, body, ctx.QueryArgs())
+	case JOB_LIST:
+		resp, err = s.listJobs(method, body, ctx.QueryArgs())
+	case JOB_STATUS:
+		resp, err = s.jobStatus(method, body, ctx.QueryArgs())
+	case JOB_RESULT:
+		resp, err = s.jobResult(method, body, ctx.QueryArgs())
+	case JOB_CANCEL:
+		err = s.cancelJob(method, body, ctx.QueryArgs())
+
 	default:
 		err = errNotFound
 	}
 
 	if err != nil {
-		resp = []byte(err.Error())
+		resp = errorBody(err)
 	}
 	setStatusCode(ctx, err)
 	if resp != nil {
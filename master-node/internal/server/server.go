@@ -2,28 +2,30 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/valyala/fasthttp"
 	"log"
 	"master-node/internal/config"
-	"master-node/internal/tasker"
+	"master-node/internal/jobs"
 	"net/http"
+	"protocol"
 )
 
 type Server struct {
 	HttpServer *fasthttp.Server
 	Debug      *http.Server
 	Cfg        *config.Config
-	taskCli    *tasker.Tasker
+	jobs       *jobs.Jobs
 }
 
 type ServerPrivate struct {
 	HttpServer *http.Server
 }
 
-func New(cfg *config.Config, taskCli *tasker.Tasker) *Server {
+func New(cfg *config.Config, js *jobs.Jobs) *Server {
 	return &Server{
-		taskCli:    taskCli,
+		jobs:       js,
 		HttpServer: new(fasthttp.Server),
 		Debug: &http.Server{
 			Addr: cfg.PrivatePort,
@@ -60,12 +62,20 @@ func (s *Server) Start() {
 }
 
 func setStatusCode(ctx *fasthttp.RequestCtx, err error) {
+	var scriptErr *protocol.ScriptError
+	if errors.As(err, &scriptErr) {
+		ctx.SetStatusCode(fasthttp.StatusUnprocessableEntity)
+		return
+	}
+
 	if err != nil {
 		switch err {
 		case errNotFound:
 			ctx.SetStatusCode(fasthttp.StatusNotFound)
 		case errMethodNotAllowed:
 			ctx.SetStatusCode(fasthttp.StatusMethodNotAllowed)
+		case protocol.ErrDraining:
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
 		default:
 			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
 		}
@@ -76,7 +86,7 @@ func setStatusCode(ctx *fasthttp.RequestCtx, err error) {
 
 func (s *Server) initRoutsServerPrivate() http.Handler {
 	privateMux := http.NewServeMux()
-	privateMux.HandleFunc("/health", func(writer http.ResponseWriter, request *http.Request) { writer.WriteHeader(http.StatusOK) })
+	privateMux.HandleFunc(protocol.HEALTH_PATH, func(writer http.ResponseWriter, request *http.Request) { writer.WriteHeader(http.StatusOK) })
 
 	return privateMux
 }
@@ -0,0 +1,271 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"master-node/internal/ranges"
+	"master-node/pkg/model"
+	"os"
+	"path/filepath"
+	"protocol"
+	"strings"
+	"sync"
+)
+
+// Record - строка JSONL файла (или файл в режиме каталога): результат одной подзадачи
+type Record struct {
+	SubtaskUUID string          `json:"SubtaskUUID"`
+	Start       uint32          `json:"Start"`
+	Amount      uint32          `json:"Amount"`
+	Data        json.RawMessage `json:"Data"`
+}
+
+/*
+Sink - движок задачи, который сохраняет результат каждой подзадачи на диск, ничего не сворачивая
+
+Режим файла: все результаты дописываются строками в один JSONL файл.
+Режим каталога: каждый результат пишется в свой файл <start>-<amount>-<uuid>.json.
+
+При открытии уже записанные результаты читаются, поэтому задача с тем же путем продолжает запись (resume).
+Повторы не записываются второй раз: результат с тем же uuid или диапазоном целиком внутри уже записанных.
+Новый запуск может разбить данные на подзадачи иначе, тогда результат частично перекрывает записанные:
+данные подзадачи не делятся, поэтому он записывается целиком и учитывается в Overlaps
+*/
+type Sink struct {
+	path   string
+	dir    bool
+	file   *os.File
+	closed bool // после Close путь может открыть другая задача
+
+	seen     map[string]struct{} // uuid подзадач уже записанных результатов
+	covered  ranges.Set          // диапазоны уже записанных результатов
+	records  int                 // кол-во записанных результатов, включая восстановленные
+	resumed  int                 // кол-во результатов, найденных при открытии
+	dups     int                 // кол-во отброшенных повторов
+	overlaps int                 // кол-во записанных результатов, частично перекрывающих уже записанные
+	failed   int                 // кол-во ошибок записи
+}
+
+// открытые приемники: две задачи не пишут в один путь одновременно
+var (
+	openPaths = make(map[string]struct{})
+	openMu    sync.Mutex
+)
+
+// Open - открытие приемника результатов. dir=true - результат каждой подзадачи в своем файле
+func Open(path string, dir bool) (*Sink, error) {
+	openMu.Lock()
+	defer openMu.Unlock()
+	if _, ok := openPaths[path]; ok {
+		return nil, fmt.Errorf("results %s are written by another job", path)
+	}
+
+	s := &Sink{
+		path: path,
+		dir:  dir,
+		seen: make(map[string]struct{}),
+	}
+
+	var err error
+	if dir {
+		err = s.openDir()
+	} else {
+		err = s.openFile()
+	}
+	if err != nil {
+		return nil, err
+	}
+	openPaths[path] = struct{}{}
+	s.resumed = s.records
+	if s.resumed > 0 {
+		log.Printf("[SINK] %s: resumed with %d results\n", path, s.resumed)
+	}
+
+	return s, nil
+}
+
+// openFile - чтение уже записанных строк. Оборванная последняя строка (падение во время записи) обрезается
+func (s *Sink) openFile() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	var good int64
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return err
+		}
+
+		var rec Record
+		if json.Unmarshal(bytes.TrimSpace(line), &rec) != nil {
+			break
+		}
+		s.remember(rec)
+		good += int64(len(line))
+	}
+
+	if err = f.Truncate(good); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err = f.Seek(good, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+	s.file = f
+
+	return nil
+}
+
+func (s *Sink) openDir() error {
+	if err := os.MkdirAll(s.path, 0o755); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.path, entry.Name()))
+		if err != nil {
+			return err
+		}
+		var rec Record
+		if json.Unmarshal(data, &rec) != nil {
+			// недописанный файл, подзадача будет записана заново
+			continue
+		}
+		s.remember(rec)
+	}
+
+	return nil
+}
+
+func (s *Sink) isDup(rec Record) bool {
+	if _, ok := s.seen[rec.SubtaskUUID]; ok && rec.SubtaskUUID != "" {
+		return true
+	}
+
+	return rec.Amount > 0 && s.covered.Covered(uint64(rec.Start), uint64(rec.Start)+uint64(rec.Amount))
+}
+
+func (s *Sink) remember(rec Record) {
+	if rec.SubtaskUUID != "" {
+		s.seen[rec.SubtaskUUID] = struct{}{}
+	}
+	if rec.Amount > 0 {
+		s.covered.Add(uint64(rec.Start), uint64(rec.Start)+uint64(rec.Amount))
+	}
+	s.records++
+}
+
+func (s *Sink) ConfirmSubtaskResult(res protocol.SubtaskResult) {
+	rec := Record{SubtaskUUID: res.SubtaskUUID, Start: res.Start, Amount: res.Amount, Data: res.Data}
+	if s.isDup(rec) {
+		s.dups++
+		log.Printf("[SINK] %s: duplicate result of subtask %s [%d, +%d) skipped\n", s.path, rec.SubtaskUUID, rec.Start, rec.Amount)
+		return
+	}
+	overlap := rec.Amount > 0 && s.covered.Overlaps(uint64(rec.Start), uint64(rec.Start)+uint64(rec.Amount))
+
+	if err := s.write(rec); err != nil {
+		s.failed++
+		log.Printf("[SINK][ERROR] %s: %v\n", s.path, err)
+		return
+	}
+	if overlap {
+		s.overlaps++
+		log.Printf("[SINK][WARNING] %s: result of subtask %s [%d, +%d) overlaps written results\n", s.path, rec.SubtaskUUID, rec.Start, rec.Amount)
+	}
+	s.remember(rec)
+}
+
+func (s *Sink) write(rec Record) error {
+	if s.closed {
+		return errors.New("sink is closed")
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	if s.dir {
+		// запись через временный файл, чтобы при падении не остался недописанный результат
+		name := filepath.Join(s.path, fmt.Sprintf("%010d-%d-%s.json", rec.Start, rec.Amount, rec.SubtaskUUID))
+		tmp := name + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o644); err != nil {
+			return err
+		}
+		return os.Rename(tmp, name)
+	}
+
+	if _, err = s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
+
+// ConfirmSubtaskHandler - результат без метаданных подзадачи, повторы не отслеживаются
+func (s *Sink) ConfirmSubtaskHandler(message json.RawMessage) {
+	s.ConfirmSubtaskResult(protocol.SubtaskResult{Data: message})
+}
+
+func (s *Sink) DoneTaskHandler() {
+	log.Printf("[SINK][DONE] %s: results: %d (resumed %d), duplicates: %d, overlaps: %d, failed: %d\n", s.path, s.records, s.resumed, s.dups, s.overlaps, s.failed)
+}
+
+func (s *Sink) ErrorTaskHandler(err error) {
+	log.Printf("[SINK] %s: Error: %v\n", s.path, err)
+}
+
+// Close - закрытие файла результатов. Tasker вызывает его на любом завершении задачи, включая отмену
+func (s *Sink) Close() error {
+	openMu.Lock()
+	defer openMu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	delete(openPaths, s.path)
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
+
+// Result - где лежат результаты и сколько их записано
+func (s *Sink) Result() interface{} {
+	return model.SinkResult{
+		Path:       s.path,
+		Records:    s.records,
+		Resumed:    s.resumed,
+		Duplicates: s.dups,
+		Overlaps:   s.overlaps,
+		Failed:     s.failed,
+	}
+}
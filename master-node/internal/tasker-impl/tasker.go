@@ -62,6 +62,11 @@ func (t *Tasker) DoneTaskHandler() {
 }
 
 func (t *Tasker) ErrorTaskHandler(err error) {
-	log.Fatalln("[TASKER] Error:", err)
+	log.Println("[TASKER] Error:", err)
 
 }
+
+// Result - лучший найденный маршрут
+func (t *Tasker) Result() interface{} {
+	return reqSubtask{Route: t.bestRoute, Cost: t.bestCost}
+}
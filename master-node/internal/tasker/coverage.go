@@ -0,0 +1,61 @@
+package tasker
+
+import (
+	"master-node/internal/ranges"
+	"master-node/pkg/model"
+	"protocol"
+)
+
+// coverage - учет принятых результатов подзадач: uuid и покрытые диапазоны [start, start+amount).
+// Менеджер может прислать результат повторно (переотправка после таймаута или ошибки доставки),
+// такие результаты отбрасываются до движка задачи. Методы вызываются под t.mu
+type coverage struct {
+	seen       map[string]struct{}
+	ranges     ranges.Set
+	duplicates int
+}
+
+func newCoverage() *coverage {
+	return &coverage{seen: make(map[string]struct{})}
+}
+
+// accept - учет результата подзадачи. false - результат уже принят
+func (c *coverage) accept(res protocol.SubtaskResult) bool {
+	if res.SubtaskUUID != "" {
+		if _, ok := c.seen[res.SubtaskUUID]; ok {
+			c.duplicates++
+			return false
+		}
+	}
+
+	start, end := uint64(res.Start), uint64(res.Start)+uint64(res.Amount)
+	if res.Amount > 0 && c.ranges.Covered(start, end) {
+		c.duplicates++
+		return false
+	}
+
+	if res.SubtaskUUID != "" {
+		c.seen[res.SubtaskUUID] = struct{}{}
+	}
+	if res.Amount > 0 {
+		c.ranges.Add(start, end)
+	}
+
+	return true
+}
+
+func (c *coverage) info() model.Coverage {
+	info := model.Coverage{
+		Subtasks:   len(c.seen),
+		Duplicates: c.duplicates,
+		Ranges:     c.ranges.Ranges(),
+	}
+	for _, r := range info.Ranges {
+		info.Covered += r.End - r.Start
+	}
+	if len(info.Ranges) > 0 && info.Ranges[0].Start == 0 {
+		info.Prefix = info.Ranges[0].End
+	}
+
+	return info
+}
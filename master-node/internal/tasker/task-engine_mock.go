@@ -19,3 +19,7 @@ func (t TaskEngineMock) DoneTaskHandler() {
 func (t TaskEngineMock) ErrorTaskHandler(err error) {
 	log.Println("ErrorTaskHandler:", err)
 }
+
+func (t TaskEngineMock) Result() interface{} {
+	return nil
+}
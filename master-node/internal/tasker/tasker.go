@@ -1,16 +1,19 @@
 package tasker
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"master-node/internal/config"
 	"master-node/pkg/model"
-	"net/http"
 	"os"
+	"protocol"
+	"protocol/client"
 	"strings"
+	"sync"
 )
 
 const (
@@ -31,32 +34,56 @@ type TaskEngine interface {
 	ConfirmSubtaskHandler(json.RawMessage)
 	DoneTaskHandler()
 	ErrorTaskHandler(err error)
+	Result() interface{} // результат задачи, вызывается между обработчиками
+}
+
+// CloseEngine - освобождение ресурсов движка, если они у него есть (io.Closer, например файл результатов)
+func CloseEngine(e TaskEngine) {
+	c, ok := e.(io.Closer)
+	if !ok {
+		return
+	}
+	if err := c.Close(); err != nil {
+		log.Println("[TASK][ERROR] close engine:", err)
+	}
+}
+
+// SubtaskResultHandler - движок, которому кроме данных нужны uuid и диапазон подзадачи.
+// Если движок его реализует, вместо ConfirmSubtaskHandler вызывается ConfirmSubtaskResult
+type SubtaskResultHandler interface {
+	ConfirmSubtaskResult(protocol.SubtaskResult)
 }
 
 type Tasker struct {
-	Task model.TaskConfig
+	Task     protocol.TaskConfig
+	TaskUUID string // uuid задачи, выданный менеджером
 
-	chTask  chan json.RawMessage
+	chTask  chan protocol.SubtaskResult
 	chError chan error
-	chDone  chan struct{}
+	chDone  chan protocol.TaskSummary
 
 	e TaskEngine
 
 	cfg *config.Config
 
-	status uint8
+	status   uint8
+	err      error
+	coverage *coverage
+	summary  *protocol.TaskSummary
+	mu       sync.Mutex
 
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-func NewTasker(ctx context.Context, cfg *config.Config, e TaskEngine, taskData json.RawMessage) (*Tasker, error) {
+func NewTasker(ctx context.Context, cfg *config.Config, e TaskEngine, task protocol.TaskConfig) (*Tasker, error) {
 	t := &Tasker{
-		cfg:     cfg,
-		e:       e,
-		chDone:  make(chan struct{}),
-		chTask:  make(chan json.RawMessage),
-		chError: make(chan error),
+		cfg:      cfg,
+		e:        e,
+		chDone:   make(chan protocol.TaskSummary),
+		chTask:   make(chan protocol.SubtaskResult),
+		chError:  make(chan error),
+		coverage: newCoverage(),
 	}
 
 	ctxT, cancel := context.WithCancel(ctx)
@@ -64,86 +91,109 @@ func NewTasker(ctx context.Context, cfg *config.Config, e TaskEngine, taskData j
 	t.ctx = ctxT
 	t.cancel = cancel
 
-	//
-	fGenerate, err := os.Open(cfg.TaskScriptGeneratePath)
-	if err != nil {
-		return nil, err
+	if err := CheckScript(task.GeneratorScript); err != nil {
+		return nil, fmt.Errorf("generator script: %v", err)
 	}
-	defer fGenerate.Close()
-	dataGenerate, err := io.ReadAll(fGenerate)
-	if err != nil {
-		return nil, err
+	if err := CheckScript(task.ComputeScript); err != nil {
+		return nil, fmt.Errorf("compute script: %v", err)
 	}
 
-	if !strings.Contains(string(dataGenerate), fmt.Sprintf("def %s(", cfg.TaskFuncNameGenerate)) {
-		return nil, fmt.Errorf("tasker Script does not contain function")
-	}
+	task.MasterUUID = cfg.UUID
+	t.Task = task
 
-	//
-	fCompute, err := os.Open(cfg.TaskScriptComputePath)
+	return t, nil
+}
+
+// LoadScript - чтение скрипта из файла
+func LoadScript(path string, funcName string) (protocol.ScriptConfig, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return protocol.ScriptConfig{}, err
 	}
-	defer fCompute.Close()
-	dataCompute, err := io.ReadAll(fCompute)
+	defer f.Close()
+	data, err := io.ReadAll(f)
 	if err != nil {
-		return nil, err
+		return protocol.ScriptConfig{}, err
 	}
 
-	if !strings.Contains(string(dataCompute), fmt.Sprintf("def %s(", cfg.TaskFuncNameCompute)) {
-		return nil, fmt.Errorf("tasker Script does not contain function")
-	}
-	//
-
-	t.Task = model.TaskConfig{
-		MasterUUID: cfg.UUID,
-		GeneratorScript: model.ScriptConfig{
-			Script:   string(dataGenerate),
-			FuncName: cfg.TaskFuncNameGenerate,
-		},
-		ComputeScript: model.ScriptConfig{
-			Script:   string(dataCompute),
-			FuncName: cfg.TaskFuncNameCompute,
-		},
-		Data: taskData,
+	return protocol.ScriptConfig{Script: string(data), FuncName: funcName}, nil
+}
+
+// CheckScript - в скрипте должна быть вызываемая функция
+func CheckScript(script protocol.ScriptConfig) error {
+	if !strings.Contains(script.Script, fmt.Sprintf("def %s(", script.FuncName)) {
+		return fmt.Errorf("tasker Script does not contain function %s", script.FuncName)
 	}
 
-	return t, nil
+	return nil
 }
 
 func (t *Tasker) Start() error {
 	t.worker()
 
-	err := t.regNode()
+	err := t.sendTaskToManager()
 	if err != nil {
+		t.cancel()
 		return err
 	}
-
-	err = t.sendTaskToManager()
-	if err != nil {
-		return err
+	// задачу отменили, пока она отправлялась: на менеджере она не должна остаться
+	if t.ctx.Err() != nil {
+		if err = t.closeTaskToManager(); err != nil {
+			log.Printf("[TASK][ERROR][TASK | %s] close cancelled task: %v\n", t.GetTaskUUID(), err)
+		}
+		return errors.New("task cancelled")
 	}
-	t.status = STATUS_SOLVING
 
 	return nil
 }
 
+/*
+worker - обработка коллбеков менеджера движком задачи
+
+После завершения, ошибки или отмены задачи воркер останавливается и закрывает движок
+*/
 func (t *Tasker) worker() {
 	go func() {
+		defer CloseEngine(t.e)
+		defer t.cancel()
+
 		for {
 			select {
 			case task := <-t.chTask:
-				t.e.ConfirmSubtaskHandler(task)
-			case <-t.chDone:
+				t.mu.Lock()
+				if !t.coverage.accept(task) {
+					log.Printf("[SUBTASK DUPLICATE][TASK | %s][SUBTASK | %s] range [%d, %d) already accepted\n", t.TaskUUID, task.SubtaskUUID, task.Start, uint64(task.Start)+uint64(task.Amount))
+					t.mu.Unlock()
+					continue
+				}
+				if h, ok := t.e.(SubtaskResultHandler); ok {
+					h.ConfirmSubtaskResult(task)
+				} else {
+					t.e.ConfirmSubtaskHandler(task.Data)
+				}
+				t.mu.Unlock()
+			case summary := <-t.chDone:
+				t.mu.Lock()
 				t.status = STATUS_DONE
+				t.summary = &summary
+				if cov := t.coverage.info(); cov.Prefix < uint64(summary.End) {
+					log.Printf("[TASK DONE][WARNING][TASK | %s] results cover [0, %d) of [0, %d)\n", t.TaskUUID, cov.Prefix, summary.End)
+				}
 				t.e.DoneTaskHandler()
+				t.mu.Unlock()
 				return
 			case err := <-t.chError:
+				t.mu.Lock()
 				t.status = STATUS_ERROR
+				t.err = err
 				t.e.ErrorTaskHandler(err)
+				t.mu.Unlock()
+				return
 
 			case <-t.ctx.Done():
+				t.mu.Lock()
 				t.status = STATUS_CLOSED
+				t.mu.Unlock()
 				return
 			}
 
@@ -152,113 +202,156 @@ func (t *Tasker) worker() {
 }
 
 func (t *Tasker) Stop() error {
-	err := t.closeTaskToManager()
+	t.mu.Lock()
+	solving := t.status == STATUS_SOLVING
+	t.mu.Unlock()
+
+	// завершенная задача на менеджере уже закрыта
+	var err error
+	if solving {
+		err = t.closeTaskToManager()
+	}
 	t.cancel()
 
 	return err
 }
 
-func (t *Tasker) AddSubtask(subtask json.RawMessage) {
-	t.chTask <- subtask
+// Done - закрывается, когда задача решена, упала или отменена
+func (t *Tasker) Done() <-chan struct{} {
+	return t.ctx.Done()
+}
+
+// AddSubtask - коллбеки менеджера после остановки воркера отбрасываются
+func (t *Tasker) AddSubtask(subtask protocol.SubtaskResult) {
+	select {
+	case t.chTask <- subtask:
+	case <-t.ctx.Done():
+	}
 }
 
-func (t *Tasker) DoneTask() {
-	t.chDone <- struct{}{}
+func (t *Tasker) DoneTask(summary protocol.TaskSummary) {
+	select {
+	case t.chDone <- summary:
+	case <-t.ctx.Done():
+	}
 }
 
 func (t *Tasker) ErrorTask(err error) {
-	t.chError <- err
+	select {
+	case t.chError <- err:
+	case <-t.ctx.Done():
+	}
+}
+
+// GetTaskUUID - uuid задачи на менеджере, пустой пока задача не отправлена
+func (t *Tasker) GetTaskUUID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.TaskUUID
 }
 
 func (t *Tasker) GetStatus() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	return statusStr[t.status]
 }
 
-func (t *Tasker) regNode() error {
-	node := model.Node{
-		UUID:        t.cfg.UUID,
-		Url:         "localhost", //todo
-		PublicPort:  t.cfg.PublicPort,
-		PrivatePort: t.cfg.PrivatePort,
-	}
+// GetError - ошибка задачи от менеджера, если задача завершилась с ошибкой
+func (t *Tasker) GetError() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	payload, err := json.Marshal(node)
+	return t.err
+}
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", t.cfg.ManagerURL, t.cfg.ManagerRegPath), bytes.NewReader(payload))
-	if err != nil {
-		return err
-	}
+// GetCoverage - принятые результаты подзадач
+func (t *Tasker) GetCoverage() model.Coverage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode/100 != 2 {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
+	return t.coverage.info()
+}
 
-		return fmt.Errorf("request failed: %s", string(body))
-	}
+// GetSummary - итог задачи от менеджера, nil пока задача решается
+func (t *Tasker) GetSummary() *protocol.TaskSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	return nil
+	return t.summary
 }
 
-func (t *Tasker) sendTaskToManager() error {
+// GetResult - текущий результат движка задачи
+func (t *Tasker) GetResult() interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	payload, err := json.Marshal(t.Task)
+	return t.e.Result()
+}
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", t.cfg.ManagerURL, t.cfg.ManagerAddPath), bytes.NewReader(payload))
-	if err != nil {
-		return err
+// GetManagerStatus - опрос менеджера о состоянии задачи (/task/status)
+func (t *Tasker) GetManagerStatus() (protocol.TaskStatus, error) {
+	taskUUID := t.GetTaskUUID()
+	if taskUUID == "" {
+		return protocol.TaskStatus{}, fmt.Errorf("task is not sent to manager")
 	}
 
-	req.URL.Query().Add("uuid", t.cfg.UUID)
+	return manager(t.cfg).TaskStatus(taskUUID)
+}
+
+// RegNode - регистрация мастера на менеджере, выполняется один раз при старте, а не для каждой задачи
+func RegNode(cfg *config.Config) error {
+	return manager(cfg).RegisterMaster(protocol.Node{
+		UUID:        cfg.UUID,
+		Url:         cfg.AdvertiseAddr,
+		PublicPort:  cfg.PublicPort,
+		PrivatePort: cfg.PrivatePort,
+		GrpcPort:    cfg.GrpcPort,
+	})
+}
+
+// RemoveNode - снятие мастера с учета на менеджере при остановке, вызывается после закрытия задач
+func RemoveNode(cfg *config.Config) error {
+	return manager(cfg).RemoveNode(cfg.UUID, false)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// sendTaskToManager - постановка задачи на менеджер. Если он отклонил скрипты, возвращается *protocol.ScriptError
+func (t *Tasker) sendTaskToManager() error {
+	taskUUID, err := manager(t.cfg).AddTask(t.Task)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode/100 != 2 {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
-
-		return fmt.Errorf("request failed: %s", string(body))
-	}
+	t.mu.Lock()
+	t.TaskUUID = taskUUID
+	t.mu.Unlock()
 
 	return nil
 }
 
 func (t *Tasker) closeTaskToManager() error {
-
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s", t.cfg.ManagerURL, t.cfg.ManagerClosePath), nil)
-	if err != nil {
-		return err
+	taskUUID := t.GetTaskUUID()
+	if taskUUID == "" {
+		return nil
 	}
 
-	req.URL.Query().Add("uuid", t.cfg.UUID)
+	return manager(t.cfg).CloseTask(taskUUID)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode/100 != 2 {
-		body, err := io.ReadAll(resp.Body)
+var (
+	managerOnce sync.Once
+	managerAPI  client.ManagerAPI
+)
+
+// manager - клиент API менеджера по транспорту из конфига. gRPC соединение одно на весь мастер
+func manager(cfg *config.Config) client.ManagerAPI {
+	managerOnce.Do(func() {
+		api, err := client.NewManagerAPI(cfg.Transport, cfg.ManagerURL, cfg.ManagerGrpcAddr, 0)
 		if err != nil {
-			return err
+			log.Fatalln("[MANAGER][ERROR]:", err)
 		}
+		managerAPI = api
+	})
 
-		return fmt.Errorf("request failed: %s", string(body))
-	}
-
-	return nil
+	return managerAPI
 }
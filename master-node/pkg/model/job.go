@@ -0,0 +1,27 @@
+package model
+
+import "protocol"
+
+// типы API задач мастера - часть протокола, их же использует клиент gridctl
+type (
+	JobScript      = protocol.JobScript
+	JobRequest     = protocol.JobRequest
+	ReducerOptions = protocol.ReducerOptions
+	SinkOptions    = protocol.SinkOptions
+	AddJobResp     = protocol.AddJobResp
+	JobInfo        = protocol.JobInfo
+	Coverage       = protocol.Coverage
+	Range          = protocol.Range
+	JobResult      = protocol.JobResult
+	JobStatus      = protocol.JobStatus
+)
+
+// SinkResult - результат движка jsonl
+type SinkResult struct {
+	Path       string `json:"Path"`
+	Records    int    `json:"Records"`    // записанные результаты, включая восстановленные
+	Resumed    int    `json:"Resumed"`    // результаты, найденные при открытии
+	Duplicates int    `json:"Duplicates"` // отброшенные повторы подзадач
+	Overlaps   int    `json:"Overlaps"`   // результаты, частично перекрывающие уже записанные
+	Failed     int    `json:"Failed"`     // ошибки записи
+}
@@ -0,0 +1,70 @@
+package protocol
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+/*
+NodeURL - адрес ноды для запросов к ней ("http://host:port"). Все URL к нодам строятся от него,
+пути методов добавляет клиент из protocol/client
+
+private - адрес приватного порта (/health), иначе публичного API ноды
+*/
+func NodeURL(node Node, private bool) string {
+	port := node.PublicPort
+	if private {
+		port = node.PrivatePort
+	}
+	u := url.URL{Scheme: "http", Host: HostPort(node.Url, port)}
+
+	return u.String()
+}
+
+/*
+HostPort - "host:port" ноды по адресу и порту из регистрации, gRPC адрес строится от него же
+
+Порт в регистрации приходит как ":8083", "8083" или "0.0.0.0:8083" - берется только номер.
+Адрес может быть со схемой, слешем в конце или IPv6 в скобках
+*/
+func HostPort(host, port string) string {
+	if i := strings.LastIndex(port, ":"); i >= 0 {
+		port = port[i+1:]
+	}
+
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "http://"), "https://")
+	host = strings.Trim(strings.TrimSuffix(host, "/"), "[]")
+
+	return net.JoinHostPort(host, port)
+}
+
+/*
+OutboundAddr - адрес интерфейса, через который нода ходит к менеджеру
+
+UDP "соединение" ничего не отправляет, только выбирает маршрут до менеджера, поэтому адрес исходящего
+интерфейса известен без обращения к сети. Пустая строка - адрес определить не удалось
+*/
+func OutboundAddr(managerURL string) string {
+	u, err := url.Parse(managerURL)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+	}
+
+	conn, err := net.Dial("udp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+
+	addr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return ""
+	}
+
+	return addr.IP.String()
+}
@@ -0,0 +1,26 @@
+package protocol
+
+import "testing"
+
+func TestNodeURL(t *testing.T) {
+	tests := []struct {
+		node    Node
+		private bool
+		want    string
+	}{
+		{Node{Url: "127.0.0.1", PublicPort: ":8083", PrivatePort: ":8084"}, false, "http://127.0.0.1:8083"},
+		{Node{Url: "127.0.0.1", PublicPort: ":8083", PrivatePort: ":8084"}, true, "http://127.0.0.1:8084"},
+		{Node{Url: "http://slave.local/", PublicPort: "8083"}, false, "http://slave.local:8083"},
+		{Node{Url: "10.0.0.5", PublicPort: "0.0.0.0:8083"}, false, "http://10.0.0.5:8083"},
+		{Node{Url: "[::1]", PublicPort: ":8083"}, false, "http://[::1]:8083"},
+	}
+	for _, tt := range tests {
+		if got := NodeURL(tt.node, tt.private); got != tt.want {
+			t.Errorf("NodeURL(%+v, %v) = %q, want %q", tt.node, tt.private, got, tt.want)
+		}
+	}
+
+	if got := HostPort("fe80::1", ":9090"); got != "[fe80::1]:9090" {
+		t.Errorf("HostPort = %q", got)
+	}
+}
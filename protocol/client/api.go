@@ -0,0 +1,55 @@
+package client
+
+import (
+	"fmt"
+	"protocol"
+	"time"
+)
+
+// ManagerAPI - методы менеджера, доступные нодам по обоим транспортам
+type ManagerAPI interface {
+	RegisterMaster(node protocol.Node) error
+	RegisterSlave(node protocol.Node) error
+	RemoveNode(uuid string, drain bool) error
+	Heartbeat(hb protocol.Heartbeat) error
+	AddTask(cfg protocol.TaskConfig) (string, error)
+	CloseTask(uuid string) error
+	TaskStatus(uuid string) (protocol.TaskStatus, error)
+	CompleteSubtask(req protocol.CompleteSubtaskRequest) error
+	ErrorSubtask(req protocol.ErrorSubtaskRequest) error
+	GetBlob(id string) ([]byte, error)
+}
+
+// SlaveAPI - методы слейва для менеджера: REST клиент или поток Dispatch
+type SlaveAPI interface {
+	AddTask(req protocol.ComputeRequest) error
+	Cancel(subtaskUUID string) error
+	CheckStatus() (string, error)
+}
+
+// MasterAPI - методы мастера для менеджера
+type MasterAPI interface {
+	SubtaskDone(res protocol.SubtaskResult) error
+	TaskDone(summary protocol.TaskSummary) error
+	TaskError(taskUUID, msg string) error
+}
+
+var (
+	_ ManagerAPI = (*Manager)(nil)
+	_ ManagerAPI = (*GRPCManager)(nil)
+	_ SlaveAPI   = (*Slave)(nil)
+	_ MasterAPI  = (*Master)(nil)
+	_ MasterAPI  = (*GRPCMaster)(nil)
+)
+
+// NewManagerAPI - клиент менеджера по транспорту ноды: REST по managerURL или gRPC по grpcAddr ("host:port")
+func NewManagerAPI(transport, managerURL, grpcAddr string, timeout time.Duration) (ManagerAPI, error) {
+	switch transport {
+	case protocol.TRANSPORT_HTTP, "":
+		return NewManager(managerURL, timeout), nil
+	case protocol.TRANSPORT_GRPC:
+		return DialManager(grpcAddr, timeout)
+	}
+
+	return nil, fmt.Errorf("unknown transport %q", transport)
+}
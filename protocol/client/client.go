@@ -0,0 +1,112 @@
+/*
+Package client - типизированные клиенты API нод: Manager, Slave и Master по REST, GRPCManager и GRPCMaster по gRPC.
+Им же пользуется gridctl
+
+REST клиент строится от базового адреса ноды ("http://host:port"), пути берутся из protocol.
+Ответ не 2xx возвращается как *protocol.StatusError, кроме ошибок протокола, у которых есть свое значение:
+protocol.ErrUnknownNode, protocol.ErrBlobNotCached, protocol.ErrDraining и *protocol.ScriptError
+*/
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"protocol"
+	"strings"
+	"time"
+)
+
+// base - общий для всех клиентов запрос к ноде
+type base struct {
+	url     string
+	timeout time.Duration
+}
+
+// endpoint - адрес метода API ноды
+func (b base) endpoint(path string, query url.Values) string {
+	u := strings.TrimSuffix(b.url, "/") + protocol.API_V1 + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	return u
+}
+
+// do - запрос с JSON телом in (nil - без тела), ответ разбирается в out (nil - ответ не нужен)
+func (b base) do(method, endpoint string, in, out interface{}) error {
+	var reqBody io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	respBody, err := b.raw(method, endpoint, reqBody)
+	if err != nil || out == nil {
+		return err
+	}
+
+	return json.Unmarshal(respBody, out)
+}
+
+// raw - запрос с телом как есть, ответ не разбирается
+func (b base) raw(method, endpoint string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: b.timeout}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, statusError(resp.StatusCode, respBody)
+	}
+
+	return respBody, nil
+}
+
+// statusError - ошибка по коду и тексту ответа
+func statusError(code int, body []byte) error {
+	text := strings.TrimSpace(string(body))
+	switch {
+	case code == http.StatusNotFound && text == protocol.ErrUnknownNode.Error():
+		return protocol.ErrUnknownNode
+	case code == http.StatusPreconditionFailed:
+		return protocol.ErrBlobNotCached
+	case code == http.StatusServiceUnavailable && text == protocol.ErrDraining.Error():
+		return protocol.ErrDraining
+	case code == http.StatusUnprocessableEntity:
+		scriptErr := &protocol.ScriptError{}
+		if json.Unmarshal(body, scriptErr) == nil && scriptErr.Stage != "" {
+			return scriptErr
+		}
+	}
+
+	return &protocol.StatusError{Code: code, Body: text}
+}
+
+// Health - проверка жизни ноды по ее приватному адресу
+func Health(privateURL string, timeout time.Duration) error {
+	b := base{url: privateURL, timeout: timeout}
+	_, err := b.raw(http.MethodGet, strings.TrimSuffix(privateURL, "/")+protocol.HEALTH_PATH, nil)
+
+	return err
+}
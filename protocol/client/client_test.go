@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"protocol"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusError(t *testing.T) {
+	scriptErr := protocol.ScriptError{Script: "compute", Stage: "syntax", Msg: "bad", Line: 1, Col: 2}
+	scriptBody, _ := json.Marshal(scriptErr)
+
+	tests := []struct {
+		code int
+		body string
+		want error
+	}{
+		{http.StatusNotFound, protocol.ErrUnknownNode.Error() + "\n", protocol.ErrUnknownNode},
+		{http.StatusPreconditionFailed, "", protocol.ErrBlobNotCached},
+		{http.StatusServiceUnavailable, protocol.ErrDraining.Error(), protocol.ErrDraining},
+	}
+	for _, tt := range tests {
+		if got := statusError(tt.code, []byte(tt.body)); got != tt.want {
+			t.Fatalf("statusError(%d, %q) = %v, want %v", tt.code, tt.body, got, tt.want)
+		}
+	}
+
+	var gotScript *protocol.ScriptError
+	if err := statusError(http.StatusUnprocessableEntity, scriptBody); !errors.As(err, &gotScript) || *gotScript != scriptErr {
+		t.Fatalf("statusError(422) = %v, want %v", err, &scriptErr)
+	}
+
+	// 404 с другим текстом - не ErrUnknownNode
+	var statusErr *protocol.StatusError
+	if err := statusError(http.StatusNotFound, []byte("no such task")); !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound || statusErr.Body != "no such task" {
+		t.Fatalf("statusError(404) = %v", err)
+	}
+}
+
+func TestManagerRoundTrip(t *testing.T) {
+	free := 1
+	sent := protocol.CompleteSubtaskRequest{SlaveUUID: "slave", SubtaskUUID: "subtask", Status: "ok", Data: json.RawMessage(`[1]`), FreeSlots: &free}
+	next := protocol.NextSubtaskResp{
+		Subtask: &protocol.ComputeRequest{UuidSubtask: "next", Amount: 3, Start: 6, DataRef: "blob"},
+		Cancel:  []string{"old"},
+	}
+
+	var received protocol.CompleteSubtaskRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, protocol.API_V1) {
+		case protocol.MANAGER_COMPLETE_SUBTASK_PATH:
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &received); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			}
+		case protocol.MANAGER_NEXT_SUBTASK_PATH:
+			if r.URL.Query().Get("free") == "0" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			json.NewEncoder(w).Encode(next)
+		case protocol.MANAGER_HEARTBEAT_PATH:
+			http.Error(w, protocol.ErrUnknownNode.Error(), http.StatusNotFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	m := NewManager(srv.URL, time.Second)
+
+	if err := m.CompleteSubtask(sent); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(received, sent) {
+		t.Fatalf("manager got %+v, want %+v", received, sent)
+	}
+
+	resp, err := m.NextSubtask("slave", 1)
+	if err != nil || !reflect.DeepEqual(resp, next) {
+		t.Fatalf("NextSubtask = %+v, %v", resp, err)
+	}
+	if resp, err = m.NextSubtask("slave", 0); err != nil || resp.Subtask != nil || resp.Cancel != nil {
+		t.Fatalf("NextSubtask without work = %+v, %v", resp, err)
+	}
+
+	if err := m.Heartbeat(protocol.Heartbeat{UUID: "slave", Kind: protocol.NODE_KIND_SLAVE}); !errors.Is(err, protocol.ErrUnknownNode) {
+		t.Fatalf("Heartbeat = %v, want %v", err, protocol.ErrUnknownNode)
+	}
+}
+
+func TestMasterJobs(t *testing.T) {
+	scriptErr := protocol.ScriptError{Script: "compute", Stage: "syntax", Msg: "bad"}
+
+	var cancelled string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, protocol.API_V1) {
+		case protocol.MASTER_JOB_ADD_PATH:
+			var req protocol.JobRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.ComputeScript.Script == "" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(w).Encode(scriptErr)
+				return
+			}
+			json.NewEncoder(w).Encode(protocol.AddJobResp{JobID: "job", TaskUUID: "task"})
+		case protocol.MASTER_JOB_STATUS_PATH:
+			json.NewEncoder(w).Encode(protocol.JobStatus{JobInfo: protocol.JobInfo{JobID: r.URL.Query().Get("id"), Status: "solving"}})
+		case protocol.MASTER_JOB_CANCEL_PATH:
+			cancelled = r.URL.Query().Get("id")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	m := NewMaster(srv.URL, time.Second)
+
+	resp, err := m.AddJob(protocol.JobRequest{ComputeScript: protocol.JobScript{Script: "def compute(x): return x"}})
+	if err != nil || resp.JobID != "job" || resp.TaskUUID != "task" {
+		t.Fatalf("AddJob = %+v, %v", resp, err)
+	}
+	var gotScript *protocol.ScriptError
+	if _, err = m.AddJob(protocol.JobRequest{}); !errors.As(err, &gotScript) || *gotScript != scriptErr {
+		t.Fatalf("AddJob with rejected script = %v, want %v", err, &scriptErr)
+	}
+
+	status, err := m.JobStatus("a b")
+	if err != nil || status.JobID != "a b" || status.Status != "solving" {
+		t.Fatalf("JobStatus = %+v, %v", status, err)
+	}
+
+	if err = m.CancelJob("job"); err != nil || cancelled != "job" {
+		t.Fatalf("CancelJob = %v, cancelled %q", err, cancelled)
+	}
+
+	var statusErr *protocol.StatusError
+	if _, err = m.JobResult("job"); !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound {
+		t.Fatalf("JobResult = %v, want 404", err)
+	}
+}
+
+func TestRunHeartbeatRegistersAgain(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, protocol.ErrUnknownNode.Error(), http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	registered := make(chan struct{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	state := func() protocol.Heartbeat { return protocol.Heartbeat{UUID: "master", Kind: protocol.NODE_KIND_MASTER} }
+	go RunHeartbeat(ctx, NewManager(srv.URL, time.Second), 10*time.Millisecond, state, func() error {
+		select {
+		case registered <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	select {
+	case <-registered:
+	case <-time.After(time.Second):
+		t.Fatal("node is not registered again after ErrUnknownNode")
+	}
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"protocol"
+	"protocol/gridpb"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// dial - соединение с gRPC сервисом ноды. Соединение ленивое: нода может быть еще не поднята
+func dial(addr string) (*grpc.ClientConn, error) {
+	return grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(gridpb.MAX_MESSAGE_SIZE),
+			grpc.MaxCallSendMsgSize(gridpb.MAX_MESSAGE_SIZE),
+		),
+	)
+}
+
+// callCtx - контекст одного вызова. timeout 0 - без ограничения
+func callCtx(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// GRPCManager - клиент менеджера по gRPC, методы повторяют Manager
+type GRPCManager struct {
+	conn    *grpc.ClientConn
+	api     gridpb.ManagerClient
+	timeout time.Duration
+}
+
+// DialManager - клиент gRPC сервиса менеджера по адресу "host:port". timeout 0 - без ограничения
+func DialManager(addr string, timeout time.Duration) (*GRPCManager, error) {
+	conn, err := dial(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCManager{conn: conn, api: gridpb.NewManagerClient(conn), timeout: timeout}, nil
+}
+
+func (m *GRPCManager) Close() error {
+	return m.conn.Close()
+}
+
+func (m *GRPCManager) RegisterMaster(node protocol.Node) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.RegisterMaster(ctx, gridpb.NewNode(node))
+	return gridpb.Error(err)
+}
+
+func (m *GRPCManager) RegisterSlave(node protocol.Node) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.RegisterSlave(ctx, gridpb.NewNode(node))
+	return gridpb.Error(err)
+}
+
+func (m *GRPCManager) RemoveNode(uuid string, drain bool) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.RemoveNode(ctx, &gridpb.RemoveNodeRequest{Uuid: uuid, Drain: drain})
+	return gridpb.Error(err)
+}
+
+func (m *GRPCManager) Heartbeat(hb protocol.Heartbeat) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.Heartbeat(ctx, gridpb.NewHeartbeat(hb))
+	return gridpb.Error(err)
+}
+
+func (m *GRPCManager) AddTask(cfg protocol.TaskConfig) (string, error) {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	resp, err := m.api.AddTask(ctx, gridpb.NewTaskConfig(cfg))
+	if err != nil {
+		return "", gridpb.Error(err)
+	}
+
+	return resp.GetTaskUuid(), nil
+}
+
+func (m *GRPCManager) CloseTask(uuid string) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.CloseTask(ctx, &gridpb.TaskRef{TaskUuid: uuid})
+	return gridpb.Error(err)
+}
+
+func (m *GRPCManager) TaskStatus(uuid string) (protocol.TaskStatus, error) {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	status, err := m.api.TaskStatus(ctx, &gridpb.TaskRef{TaskUuid: uuid})
+	if err != nil {
+		return protocol.TaskStatus{}, gridpb.Error(err)
+	}
+
+	return status.Protocol(), nil
+}
+
+func (m *GRPCManager) CompleteSubtask(req protocol.CompleteSubtaskRequest) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.CompleteSubtask(ctx, gridpb.NewCompleteSubtaskRequest(req))
+	return gridpb.Error(err)
+}
+
+func (m *GRPCManager) ErrorSubtask(req protocol.ErrorSubtaskRequest) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.ErrorSubtask(ctx, gridpb.NewErrorSubtaskRequest(req))
+	return gridpb.Error(err)
+}
+
+func (m *GRPCManager) GetBlob(id string) ([]byte, error) {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	blob, err := m.api.GetBlob(ctx, &gridpb.BlobRef{Id: id})
+	if err != nil {
+		return nil, gridpb.Error(err)
+	}
+
+	return blob.GetData(), nil
+}
+
+// Dispatch - поток подзадач слейва, живет до отмены ctx. Первым сообщением слейв отправляет hello
+func (m *GRPCManager) Dispatch(ctx context.Context) (gridpb.Manager_DispatchClient, error) {
+	stream, err := m.api.Dispatch(ctx)
+	return stream, gridpb.Error(err)
+}
+
+// GRPCMaster - клиент мастера по gRPC, методы повторяют Master
+type GRPCMaster struct {
+	conn    *grpc.ClientConn
+	api     gridpb.MasterClient
+	timeout time.Duration
+}
+
+// DialMaster - клиент gRPC сервиса мастера по адресу "host:port". timeout 0 - без ограничения
+func DialMaster(addr string, timeout time.Duration) (*GRPCMaster, error) {
+	conn, err := dial(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCMaster{conn: conn, api: gridpb.NewMasterClient(conn), timeout: timeout}, nil
+}
+
+func (m *GRPCMaster) Close() error {
+	return m.conn.Close()
+}
+
+func (m *GRPCMaster) SubtaskDone(res protocol.SubtaskResult) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.SubtaskDone(ctx, gridpb.NewSubtaskResult(res))
+	return gridpb.Error(err)
+}
+
+func (m *GRPCMaster) TaskDone(summary protocol.TaskSummary) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.TaskDone(ctx, gridpb.NewTaskSummary(summary))
+	return gridpb.Error(err)
+}
+
+func (m *GRPCMaster) TaskError(taskUUID, msg string) error {
+	ctx, cancel := callCtx(m.timeout)
+	defer cancel()
+
+	_, err := m.api.TaskError(ctx, &gridpb.TaskError{TaskUuid: taskUUID, Error: msg})
+	return gridpb.Error(err)
+}
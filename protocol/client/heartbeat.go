@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log"
+	"protocol"
+	"time"
+)
+
+/*
+RunHeartbeat - отправка heartbeat менеджеру каждые interval, пока не отменен ctx. Общий цикл мастера и слейва
+
+state - текущее состояние ноды. Если менеджер ответил, что нода ему не известна, она регистрируется заново через register
+*/
+func RunHeartbeat(ctx context.Context, manager ManagerAPI, interval time.Duration, state func() protocol.Heartbeat, register func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := manager.Heartbeat(state())
+			if errors.Is(err, protocol.ErrUnknownNode) {
+				log.Println("[HEARTBEAT] manager does not know the node, registering again")
+				err = register()
+			}
+			if err != nil {
+				log.Println("[HEARTBEAT][ERROR]", err)
+			}
+		}
+	}
+}
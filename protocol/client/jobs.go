@@ -0,0 +1,44 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"protocol"
+)
+
+// AddJob - постановка задачи на мастер. Отклоненные при проверке скрипты - *protocol.ScriptError
+func (m *Master) AddJob(req protocol.JobRequest) (protocol.AddJobResp, error) {
+	var resp protocol.AddJobResp
+	err := m.do(http.MethodPost, m.endpoint(protocol.MASTER_JOB_ADD_PATH, nil), req, &resp)
+
+	return resp, err
+}
+
+// ListJobs - задачи мастера
+func (m *Master) ListJobs() ([]protocol.JobInfo, error) {
+	var jobs []protocol.JobInfo
+	err := m.do(http.MethodGet, m.endpoint(protocol.MASTER_JOB_LIST_PATH, nil), nil, &jobs)
+
+	return jobs, err
+}
+
+// JobStatus - состояние задачи на мастере и на менеджере
+func (m *Master) JobStatus(id string) (protocol.JobStatus, error) {
+	var status protocol.JobStatus
+	err := m.do(http.MethodGet, m.endpoint(protocol.MASTER_JOB_STATUS_PATH, url.Values{"id": {id}}), nil, &status)
+
+	return status, err
+}
+
+// JobResult - результат задачи, для незавершенной - текущий
+func (m *Master) JobResult(id string) (protocol.JobResult, error) {
+	var result protocol.JobResult
+	err := m.do(http.MethodGet, m.endpoint(protocol.MASTER_JOB_RESULT_PATH, url.Values{"id": {id}}), nil, &result)
+
+	return result, err
+}
+
+// CancelJob - отмена задачи на мастере и на менеджере
+func (m *Master) CancelJob(id string) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MASTER_JOB_CANCEL_PATH, url.Values{"id": {id}}), nil, nil)
+}
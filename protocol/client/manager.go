@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"protocol"
+	"strconv"
+	"time"
+)
+
+// Manager - клиент API менеджера для мастеров, слейвов и gridctl
+type Manager struct {
+	base
+}
+
+// NewManager - клиент менеджера по его адресу, например "http://localhost:8080". timeout 0 - без ограничения
+func NewManager(managerURL string, timeout time.Duration) *Manager {
+	return &Manager{base{url: managerURL, timeout: timeout}}
+}
+
+func (m *Manager) RegisterMaster(node protocol.Node) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MANAGER_REGISTER_MASTER_PATH, nil), node, nil)
+}
+
+func (m *Manager) RegisterSlave(node protocol.Node) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MANAGER_REGISTER_SLAVE_PATH, nil), node, nil)
+}
+
+// RemoveNode - снятие ноды с учета. С drain=true слейв только перестает получать подзадачи
+func (m *Manager) RemoveNode(uuid string, drain bool) error {
+	query := url.Values{"uuid": {uuid}, "drain": {strconv.FormatBool(drain)}}
+
+	return m.do(http.MethodDelete, m.endpoint(protocol.MANAGER_REMOVE_NODE_PATH, query), nil, nil)
+}
+
+// Heartbeat - сигнал жизни. protocol.ErrUnknownNode - ноду нужно зарегистрировать заново
+func (m *Manager) Heartbeat(hb protocol.Heartbeat) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MANAGER_HEARTBEAT_PATH, nil), hb, nil)
+}
+
+func (m *Manager) ListNodes() ([]protocol.NodeInfo, error) {
+	var nodes []protocol.NodeInfo
+	err := m.do(http.MethodGet, m.endpoint(protocol.MANAGER_LIST_NODES_PATH, nil), nil, &nodes)
+
+	return nodes, err
+}
+
+// AddTask - постановка задачи, возвращает ее uuid. *protocol.ScriptError - задача отклонена при проверке скриптов
+func (m *Manager) AddTask(cfg protocol.TaskConfig) (string, error) {
+	var resp protocol.AddTaskResp
+	err := m.do(http.MethodPost, m.endpoint(protocol.MANAGER_ADD_TASK_PATH, nil), cfg, &resp)
+
+	return resp.TaskUUID, err
+}
+
+func (m *Manager) CloseTask(uuid string) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MANAGER_CLOSE_TASK_PATH, url.Values{"uuid": {uuid}}), nil, nil)
+}
+
+func (m *Manager) TaskStatus(uuid string) (protocol.TaskStatus, error) {
+	var status protocol.TaskStatus
+	err := m.do(http.MethodGet, m.endpoint(protocol.MANAGER_TASK_STATUS_PATH, url.Values{"uuid": {uuid}}), nil, &status)
+
+	return status, err
+}
+
+func (m *Manager) ListTasks() ([]protocol.TaskStatus, error) {
+	var tasks []protocol.TaskStatus
+	err := m.do(http.MethodGet, m.endpoint(protocol.MANAGER_LIST_TASKS_PATH, nil), nil, &tasks)
+
+	return tasks, err
+}
+
+func (m *Manager) CompleteSubtask(req protocol.CompleteSubtaskRequest) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MANAGER_COMPLETE_SUBTASK_PATH, nil), req, nil)
+}
+
+func (m *Manager) ErrorSubtask(req protocol.ErrorSubtaskRequest) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MANAGER_ERROR_SUBTASK_PATH, nil), req, nil)
+}
+
+/*
+NextSubtask - следующая подзадача для слейва в режиме pull (длинный опрос)
+
+freeSlots - свободные воркеры слейва: при 0 менеджер не выдает подзадачу, а только ждет отмен для слейва
+или освобождения его слота. Менеджер держит запрос, пока не появится ответ или не истечет время ожидания,
+в последнем случае ответ пустой и нужно спросить снова. Если у клиента задан timeout, менеджер ждет
+не дольше 3/4 от него, чтобы ответ с подзадачей успел дойти до слейва
+*/
+func (m *Manager) NextSubtask(slaveUUID string, freeSlots int) (resp protocol.NextSubtaskResp, err error) {
+	query := url.Values{"uuid": {slaveUUID}, "free": {strconv.Itoa(freeSlots)}}
+	if m.timeout > 0 {
+		query.Set("wait", (m.timeout * 3 / 4).String())
+	}
+	respBody, err := m.raw(http.MethodGet, m.endpoint(protocol.MANAGER_NEXT_SUBTASK_PATH, query), nil)
+	if err != nil || len(respBody) == 0 {
+		return resp, err
+	}
+	err = json.Unmarshal(respBody, &resp)
+
+	return resp, err
+}
+
+// PutBlob - загрузка блоба, возвращает его id
+func (m *Manager) PutBlob(data []byte) (string, error) {
+	respBody, err := m.raw(http.MethodPost, m.endpoint(protocol.MANAGER_BLOB_PATH, nil), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	var resp protocol.BlobResp
+	err = json.Unmarshal(respBody, &resp)
+
+	return resp.ID, err
+}
+
+// GetBlob - содержимое блоба по id
+func (m *Manager) GetBlob(id string) ([]byte, error) {
+	return m.raw(http.MethodGet, m.endpoint(protocol.MANAGER_BLOB_PATH, url.Values{"id": {id}}), nil)
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"protocol"
+	"time"
+)
+
+// Master - клиент API мастера: колбэки менеджера и задачи мастера для клиентов (jobs.go)
+type Master struct {
+	base
+}
+
+// NewMaster - клиент мастера по адресу его публичного порта. timeout 0 - без ограничения
+func NewMaster(masterURL string, timeout time.Duration) *Master {
+	return &Master{base{url: masterURL, timeout: timeout}}
+}
+
+// SubtaskDone - решенная подзадача задачи res.TaskUUID
+func (m *Master) SubtaskDone(res protocol.SubtaskResult) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MASTER_SUBTASK_DONE_PATH, url.Values{"uuid": {res.TaskUUID}}), res, nil)
+}
+
+// TaskDone - задача решена целиком
+func (m *Master) TaskDone(summary protocol.TaskSummary) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MASTER_TASK_DONE_PATH, url.Values{"uuid": {summary.TaskUUID}}), summary, nil)
+}
+
+// TaskError - задача завершилась с ошибкой, msg - ее текст
+func (m *Master) TaskError(taskUUID, msg string) error {
+	return m.do(http.MethodPost, m.endpoint(protocol.MASTER_TASK_ERROR_PATH, url.Values{"uuid": {taskUUID}}), msg, nil)
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"protocol"
+	"time"
+)
+
+// Slave - клиент API слейва для менеджера
+type Slave struct {
+	base
+}
+
+// NewSlave - клиент слейва по адресу его публичного порта. timeout 0 - без ограничения
+func NewSlave(slaveURL string, timeout time.Duration) *Slave {
+	return &Slave{base{url: slaveURL, timeout: timeout}}
+}
+
+// AddTask - отправка подзадачи. protocol.ErrBlobNotCached - подзадачу нужно отправить со скриптами и данными
+func (s *Slave) AddTask(req protocol.ComputeRequest) error {
+	return s.do(http.MethodPost, s.endpoint(protocol.SLAVE_ADD_TASK_PATH, nil), req, nil)
+}
+
+// Cancel - отмена подзадачи на слейве
+func (s *Slave) Cancel(subtaskUUID string) error {
+	return s.do(http.MethodPost, s.endpoint(protocol.SLAVE_CANCEL_PATH, url.Values{"subtask": {subtaskUUID}}), nil, nil)
+}
+
+// CheckStatus - статус слейва текстом, как в Heartbeat.Status
+func (s *Slave) CheckStatus() (string, error) {
+	body, err := s.raw(http.MethodGet, s.endpoint(protocol.SLAVE_CHECK_STATUS_PATH, nil), nil)
+
+	return string(body), err
+}
@@ -0,0 +1,26 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ошибки, которые передаются между нодами текстом ответа
+var (
+	// ErrUnknownNode - менеджер не знает ноду (404 на /node/heartbeat), ноде нужно зарегистрироваться заново
+	ErrUnknownNode = errors.New("unknown node")
+	// ErrBlobNotCached - слейв не смог получить блоб по id (412 на /addTask), подзадачу нужно отправить целиком
+	ErrBlobNotCached = errors.New("blob not cached")
+	// ErrDraining - слейв останавливается и не принимает новые подзадачи (503 на /addTask)
+	ErrDraining = errors.New("node is draining")
+)
+
+// StatusError - ответ ноды с кодом не 2xx
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.Code, e.Body)
+}
@@ -0,0 +1,212 @@
+package gridpb
+
+import "protocol"
+
+// Конвертеры между сообщениями gRPC и JSON типами пакета protocol: New* - из protocol, Protocol() - обратно
+
+func NewNode(n protocol.Node) *Node {
+	return &Node{
+		Uuid:        n.UUID,
+		Url:         n.Url,
+		PublicPort:  n.PublicPort,
+		PrivatePort: n.PrivatePort,
+		Cpu:         int32(n.CPU),
+		Slots:       int32(n.Slots),
+		Benchmark:   n.Benchmark,
+		GrpcPort:    n.GrpcPort,
+		Pull:        n.Pull,
+	}
+}
+
+func (x *Node) Protocol() protocol.Node {
+	return protocol.Node{
+		UUID:        x.GetUuid(),
+		Url:         x.GetUrl(),
+		PublicPort:  x.GetPublicPort(),
+		PrivatePort: x.GetPrivatePort(),
+		CPU:         int(x.GetCpu()),
+		Slots:       int(x.GetSlots()),
+		Benchmark:   x.GetBenchmark(),
+		GrpcPort:    x.GetGrpcPort(),
+		Pull:        x.GetPull(),
+	}
+}
+
+func NewHeartbeat(hb protocol.Heartbeat) *Heartbeat {
+	return &Heartbeat{
+		Uuid:     hb.UUID,
+		Kind:     hb.Kind,
+		Status:   hb.Status,
+		Subtasks: hb.Subtasks,
+		Load:     int32(hb.Load),
+		Version:  hb.Version,
+	}
+}
+
+func (x *Heartbeat) Protocol() protocol.Heartbeat {
+	return protocol.Heartbeat{
+		UUID:     x.GetUuid(),
+		Kind:     x.GetKind(),
+		Status:   x.GetStatus(),
+		Subtasks: x.GetSubtasks(),
+		Load:     int(x.GetLoad()),
+		Version:  x.GetVersion(),
+	}
+}
+
+func NewScriptConfig(s protocol.ScriptConfig) *ScriptConfig {
+	return &ScriptConfig{Script: s.Script, FuncName: s.FuncName, Hash: s.Hash}
+}
+
+func (x *ScriptConfig) Protocol() protocol.ScriptConfig {
+	return protocol.ScriptConfig{Script: x.GetScript(), FuncName: x.GetFuncName(), Hash: x.GetHash()}
+}
+
+func NewExecutionLimits(l protocol.ExecutionLimits) *ExecutionLimits {
+	return &ExecutionLimits{MaxSteps: l.MaxSteps, TimeoutMs: l.TimeoutMs, MaxResultBytes: int64(l.MaxResultBytes)}
+}
+
+func (x *ExecutionLimits) Protocol() protocol.ExecutionLimits {
+	return protocol.ExecutionLimits{MaxSteps: x.GetMaxSteps(), TimeoutMs: x.GetTimeoutMs(), MaxResultBytes: int(x.GetMaxResultBytes())}
+}
+
+func NewTaskConfig(cfg protocol.TaskConfig) *TaskConfig {
+	return &TaskConfig{
+		MasterUuid:      cfg.MasterUUID,
+		GeneratorScript: NewScriptConfig(cfg.GeneratorScript),
+		ComputeScript:   NewScriptConfig(cfg.ComputeScript),
+		Data:            cfg.Data,
+		DataRef:         cfg.DataRef,
+		Limits:          NewExecutionLimits(cfg.Limits),
+	}
+}
+
+func (x *TaskConfig) Protocol() protocol.TaskConfig {
+	return protocol.TaskConfig{
+		MasterUUID:      x.GetMasterUuid(),
+		GeneratorScript: x.GetGeneratorScript().Protocol(),
+		ComputeScript:   x.GetComputeScript().Protocol(),
+		Data:            x.GetData(),
+		DataRef:         x.GetDataRef(),
+		Limits:          x.GetLimits().Protocol(),
+	}
+}
+
+func NewTaskStatus(s protocol.TaskStatus) *TaskStatus {
+	return &TaskStatus{
+		TaskUuid:   s.TaskUUID,
+		MasterUuid: s.MasterUUID,
+		Status:     s.Status,
+		Counter:    s.Counter,
+		InFlight:   int32(s.InFlight),
+		Completed:  int32(s.Completed),
+		Failed:     int32(s.Failed),
+		Exhausted:  s.Exhausted,
+		End:        s.End,
+		Size:       s.Size,
+		Progress:   s.Progress,
+		Slaves:     s.Slaves,
+	}
+}
+
+func (x *TaskStatus) Protocol() protocol.TaskStatus {
+	s := protocol.TaskStatus{
+		TaskUUID:   x.GetTaskUuid(),
+		MasterUUID: x.GetMasterUuid(),
+		Status:     x.GetStatus(),
+		Counter:    x.GetCounter(),
+		InFlight:   int(x.GetInFlight()),
+		Completed:  int(x.GetCompleted()),
+		Failed:     int(x.GetFailed()),
+		Exhausted:  x.GetExhausted(),
+		End:        x.GetEnd(),
+		Size:       x.Size,
+		Progress:   x.Progress,
+		Slaves:     x.GetSlaves(),
+	}
+	if s.Slaves == nil {
+		s.Slaves = []string{}
+	}
+
+	return s
+}
+
+func NewComputeRequest(req protocol.ComputeRequest) *ComputeRequest {
+	return &ComputeRequest{
+		SubtaskUuid: req.UuidSubtask,
+		Generate:    NewScriptConfig(req.Generate),
+		Compute:     NewScriptConfig(req.Compute),
+		Data:        req.Data,
+		DataRef:     req.DataRef,
+		Amount:      req.Amount,
+		Start:       req.Start,
+		Limits:      NewExecutionLimits(req.Limits),
+	}
+}
+
+func (x *ComputeRequest) Protocol() protocol.ComputeRequest {
+	return protocol.ComputeRequest{
+		UuidSubtask: x.GetSubtaskUuid(),
+		Generate:    x.GetGenerate().Protocol(),
+		Compute:     x.GetCompute().Protocol(),
+		Data:        x.GetData(),
+		DataRef:     x.GetDataRef(),
+		Amount:      x.GetAmount(),
+		Start:       x.GetStart(),
+		Limits:      x.GetLimits().Protocol(),
+	}
+}
+
+func NewCompleteSubtaskRequest(req protocol.CompleteSubtaskRequest) *CompleteSubtaskRequest {
+	msg := &CompleteSubtaskRequest{
+		SlaveUuid:   req.SlaveUUID,
+		SubtaskUuid: req.SubtaskUUID,
+		Status:      req.Status,
+		Data:        req.Data,
+	}
+	if req.FreeSlots != nil {
+		free := int32(*req.FreeSlots)
+		msg.FreeSlots = &free
+	}
+
+	return msg
+}
+
+func (x *CompleteSubtaskRequest) Protocol() protocol.CompleteSubtaskRequest {
+	req := protocol.CompleteSubtaskRequest{
+		SlaveUUID:   x.GetSlaveUuid(),
+		SubtaskUUID: x.GetSubtaskUuid(),
+		Status:      x.GetStatus(),
+		Data:        x.GetData(),
+	}
+	if x.FreeSlots != nil {
+		free := int(*x.FreeSlots)
+		req.FreeSlots = &free
+	}
+
+	return req
+}
+
+func NewErrorSubtaskRequest(req protocol.ErrorSubtaskRequest) *ErrorSubtaskRequest {
+	return &ErrorSubtaskRequest{SlaveUuid: req.SlaveUUID, SubtaskUuid: req.SubtaskUUID, Type: req.Type, Error: req.Error}
+}
+
+func (x *ErrorSubtaskRequest) Protocol() protocol.ErrorSubtaskRequest {
+	return protocol.ErrorSubtaskRequest{SlaveUUID: x.GetSlaveUuid(), SubtaskUUID: x.GetSubtaskUuid(), Type: x.GetType(), Error: x.GetError()}
+}
+
+func NewSubtaskResult(res protocol.SubtaskResult) *SubtaskResult {
+	return &SubtaskResult{TaskUuid: res.TaskUUID, SubtaskUuid: res.SubtaskUUID, Start: res.Start, Amount: res.Amount, Data: res.Data}
+}
+
+func (x *SubtaskResult) Protocol() protocol.SubtaskResult {
+	return protocol.SubtaskResult{TaskUUID: x.GetTaskUuid(), SubtaskUUID: x.GetSubtaskUuid(), Start: x.GetStart(), Amount: x.GetAmount(), Data: x.GetData()}
+}
+
+func NewTaskSummary(s protocol.TaskSummary) *TaskSummary {
+	return &TaskSummary{TaskUuid: s.TaskUUID, End: s.End, Completed: int32(s.Completed), Failed: int32(s.Failed), Dropped: int32(s.Dropped)}
+}
+
+func (x *TaskSummary) Protocol() protocol.TaskSummary {
+	return protocol.TaskSummary{TaskUUID: x.GetTaskUuid(), End: x.GetEnd(), Completed: int(x.GetCompleted()), Failed: int(x.GetFailed()), Dropped: int(x.GetDropped())}
+}
@@ -0,0 +1,160 @@
+package gridpb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"protocol"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// roundTrip - сообщение проходит через protobuf, как по сети
+func roundTrip[T proto.Message](t *testing.T, msg T, out T) T {
+	t.Helper()
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proto.Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+
+	return out
+}
+
+func TestCompleteSubtaskRequestConvert(t *testing.T) {
+	free := 2
+	tests := []protocol.CompleteSubtaskRequest{
+		{SlaveUUID: "slave", SubtaskUUID: "subtask", Status: "ok", Data: json.RawMessage(`{"best":1}`), FreeSlots: &free},
+		{SlaveUUID: "slave", SubtaskUUID: "subtask", Status: "error", Data: json.RawMessage(`"boom"`)},
+	}
+
+	for _, want := range tests {
+		got := roundTrip(t, NewCompleteSubtaskRequest(want), &CompleteSubtaskRequest{}).Protocol()
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	size := uint32(24)
+	progress := 25.0
+
+	node := protocol.Node{UUID: "slave", Url: "10.0.0.2", PublicPort: ":8083", PrivatePort: ":8084", CPU: 4, Slots: 2, Benchmark: 1.5, Pull: true}
+	if got := roundTrip(t, NewNode(node), &Node{}).Protocol(); !reflect.DeepEqual(got, node) {
+		t.Fatalf("Node: got %+v, want %+v", got, node)
+	}
+
+	hb := protocol.Heartbeat{UUID: "slave", Kind: protocol.NODE_KIND_SLAVE, Status: "busy", Subtasks: []string{"s1", "s2"}, Load: 2, Version: "1.0.0"}
+	if got := roundTrip(t, NewHeartbeat(hb), &Heartbeat{}).Protocol(); !reflect.DeepEqual(got, hb) {
+		t.Fatalf("Heartbeat: got %+v, want %+v", got, hb)
+	}
+
+	cfg := protocol.TaskConfig{
+		MasterUUID:      "master",
+		GeneratorScript: protocol.ScriptConfig{Script: "x", FuncName: "gen", Hash: "h1"},
+		ComputeScript:   protocol.ScriptConfig{FuncName: "compute", Hash: "h2"},
+		Data:            json.RawMessage(`{"n":4}`),
+		DataRef:         "blob",
+		Limits:          protocol.ExecutionLimits{MaxSteps: 100, TimeoutMs: 50, MaxResultBytes: 10},
+	}
+	if got := roundTrip(t, NewTaskConfig(cfg), &TaskConfig{}).Protocol(); !reflect.DeepEqual(got, cfg) {
+		t.Fatalf("TaskConfig: got %+v, want %+v", got, cfg)
+	}
+
+	st := protocol.TaskStatus{
+		TaskUUID: "task", MasterUUID: "master", Status: "solving", Counter: 12, InFlight: 2, Completed: 3, Failed: 1,
+		Exhausted: true, End: 24, Size: &size, Progress: &progress, Slaves: []string{"a"},
+	}
+	if got := roundTrip(t, NewTaskStatus(st), &TaskStatus{}).Protocol(); !reflect.DeepEqual(got, st) {
+		t.Fatalf("TaskStatus: got %+v, want %+v", got, st)
+	}
+
+	req := protocol.ComputeRequest{
+		UuidSubtask: "subtask",
+		Generate:    protocol.ScriptConfig{Script: "x", FuncName: "gen"},
+		Compute:     protocol.ScriptConfig{Script: "y", FuncName: "compute"},
+		Data:        json.RawMessage(`[1,2]`),
+		Amount:      5,
+		Start:       10,
+		Limits:      protocol.ExecutionLimits{MaxSteps: 1},
+	}
+	if got := roundTrip(t, NewComputeRequest(req), &ComputeRequest{}).Protocol(); !reflect.DeepEqual(got, req) {
+		t.Fatalf("ComputeRequest: got %+v, want %+v", got, req)
+	}
+
+	errReq := protocol.ErrorSubtaskRequest{SlaveUUID: "slave", SubtaskUUID: "subtask", Type: protocol.ERROR_SCRIPT, Error: "boom"}
+	if got := roundTrip(t, NewErrorSubtaskRequest(errReq), &ErrorSubtaskRequest{}).Protocol(); got != errReq {
+		t.Fatalf("ErrorSubtaskRequest: got %+v, want %+v", got, errReq)
+	}
+
+	res := protocol.SubtaskResult{TaskUUID: "task", SubtaskUUID: "subtask", Start: 5, Amount: 5, Data: json.RawMessage(`{}`)}
+	if got := roundTrip(t, NewSubtaskResult(res), &SubtaskResult{}).Protocol(); !reflect.DeepEqual(got, res) {
+		t.Fatalf("SubtaskResult: got %+v, want %+v", got, res)
+	}
+
+	sum := protocol.TaskSummary{TaskUUID: "task", End: 24, Completed: 6, Failed: 1, Dropped: 2}
+	if got := roundTrip(t, NewTaskSummary(sum), &TaskSummary{}).Protocol(); got != sum {
+		t.Fatalf("TaskSummary: got %+v, want %+v", got, sum)
+	}
+}
+
+func TestAckRoundTrip(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{nil, ""},
+		{protocol.ErrUnknownNode, ACK_UNKNOWN_NODE},
+		{fmt.Errorf("add task: %w", protocol.ErrBlobNotCached), ACK_BLOB_NOT_CACHED},
+		{protocol.ErrDraining, ACK_DRAINING},
+		{errors.New("disk full"), ACK_ERROR},
+	}
+
+	for _, tt := range tests {
+		ack := roundTrip(t, NewAck(tt.err), &Ack{})
+		if ack.GetCode() != tt.code {
+			t.Fatalf("NewAck(%v).Code = %q, want %q", tt.err, ack.GetCode(), tt.code)
+		}
+		got := ack.Err()
+		switch {
+		case tt.err == nil:
+			if got != nil {
+				t.Fatalf("Err() = %v, want nil", got)
+			}
+		case tt.code == ACK_ERROR:
+			if got == nil || got.Error() != tt.err.Error() {
+				t.Fatalf("Err() = %v, want %v", got, tt.err)
+			}
+		default:
+			if !errors.Is(tt.err, got) {
+				t.Fatalf("Err() = %v, want %v", got, tt.err)
+			}
+		}
+	}
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	scriptErr := &protocol.ScriptError{Script: "generator", Stage: "exec", Msg: "division by zero", Line: 2, Col: 5}
+	tests := []error{protocol.ErrUnknownNode, protocol.ErrBlobNotCached, protocol.ErrDraining}
+	for _, want := range tests {
+		if got := Error(Status(want)); got != want {
+			t.Fatalf("Error(Status(%v)) = %v", want, got)
+		}
+	}
+
+	var got *protocol.ScriptError
+	if err := Error(Status(scriptErr)); !errors.As(err, &got) || *got != *scriptErr {
+		t.Fatalf("Error(Status(%v)) = %v", scriptErr, err)
+	}
+	if err := Error(Status(errors.New("disk full"))); err == nil || err.Error() != "disk full" {
+		t.Fatalf("Error(Status(disk full)) = %v", err)
+	}
+	if Status(nil) != nil || Error(nil) != nil {
+		t.Fatal("nil error must stay nil")
+	}
+}
@@ -0,0 +1,113 @@
+package gridpb
+
+import (
+	"encoding/json"
+	"errors"
+	"protocol"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MAX_MESSAGE_SIZE - предел сообщения gRPC на обеих сторонах: данные задач и блобы бывают больше стандартных 4 МБ
+const MAX_MESSAGE_SIZE = 64 << 20
+
+// коды Ack в потоке Dispatch
+const (
+	ACK_UNKNOWN_NODE    = "unknown_node"
+	ACK_BLOB_NOT_CACHED = "blob_not_cached"
+	ACK_DRAINING        = "draining"
+	ACK_ERROR           = "error"
+)
+
+/*
+Status - ошибка обработчика для ответа по gRPC
+
+Ошибки протокола получают свои коды, как в REST: ErrUnknownNode - NotFound, ErrBlobNotCached - FailedPrecondition,
+ErrDraining - Unavailable, *protocol.ScriptError - InvalidArgument с JSON ошибки в тексте
+*/
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var scriptErr *protocol.ScriptError
+	switch {
+	case errors.Is(err, protocol.ErrUnknownNode):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, protocol.ErrBlobNotCached):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, protocol.ErrDraining):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.As(err, &scriptErr):
+		data, _ := json.Marshal(scriptErr)
+		return status.Error(codes.InvalidArgument, string(data))
+	}
+
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// Error - ответ gRPC обратно в ошибку протокола. Ошибки соединения возвращаются как есть
+func Error(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+
+	msg := st.Message()
+	switch st.Code() {
+	case codes.NotFound:
+		if msg == protocol.ErrUnknownNode.Error() {
+			return protocol.ErrUnknownNode
+		}
+	case codes.FailedPrecondition:
+		if msg == protocol.ErrBlobNotCached.Error() {
+			return protocol.ErrBlobNotCached
+		}
+	case codes.Unavailable:
+		if msg == protocol.ErrDraining.Error() {
+			return protocol.ErrDraining
+		}
+	case codes.InvalidArgument:
+		scriptErr := &protocol.ScriptError{}
+		if json.Unmarshal([]byte(msg), scriptErr) == nil && scriptErr.Stage != "" {
+			return scriptErr
+		}
+	case codes.Unknown:
+		return errors.New(msg)
+	}
+
+	return err
+}
+
+// NewAck - ответ на запрос в потоке Dispatch, ошибки протокола передаются кодом
+func NewAck(err error) *Ack {
+	switch {
+	case err == nil:
+		return &Ack{}
+	case errors.Is(err, protocol.ErrUnknownNode):
+		return &Ack{Code: ACK_UNKNOWN_NODE, Error: err.Error()}
+	case errors.Is(err, protocol.ErrBlobNotCached):
+		return &Ack{Code: ACK_BLOB_NOT_CACHED, Error: err.Error()}
+	case errors.Is(err, protocol.ErrDraining):
+		return &Ack{Code: ACK_DRAINING, Error: err.Error()}
+	}
+
+	return &Ack{Code: ACK_ERROR, Error: err.Error()}
+}
+
+// Err - ошибка из ответа, nil - запрос выполнен
+func (x *Ack) Err() error {
+	switch x.GetCode() {
+	case "":
+		return nil
+	case ACK_UNKNOWN_NODE:
+		return protocol.ErrUnknownNode
+	case ACK_BLOB_NOT_CACHED:
+		return protocol.ErrBlobNotCached
+	case ACK_DRAINING:
+		return protocol.ErrDraining
+	}
+
+	return errors.New(x.GetError())
+}
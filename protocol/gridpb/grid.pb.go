@@ -0,0 +1,2038 @@
+// gRPC транспорт между нодами. Сообщения повторяют JSON типы пакета protocol,
+// данные задач и результаты подзадач передаются как JSON в bytes.
+//
+// Сгенерированный код обновляется из каталога protocol:
+//   protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative gridpb/grid.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        v5.29.3
+// source: gridpb/grid.proto
+
+package gridpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Empty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_gridpb_grid_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{0}
+}
+
+// Node - регистрация ноды, как protocol.Node
+type Node struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	PublicPort    string                 `protobuf:"bytes,3,opt,name=public_port,json=publicPort,proto3" json:"public_port,omitempty"`
+	PrivatePort   string                 `protobuf:"bytes,4,opt,name=private_port,json=privatePort,proto3" json:"private_port,omitempty"`
+	Cpu           int32                  `protobuf:"varint,5,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Slots         int32                  `protobuf:"varint,6,opt,name=slots,proto3" json:"slots,omitempty"`
+	Benchmark     float64                `protobuf:"fixed64,7,opt,name=benchmark,proto3" json:"benchmark,omitempty"`
+	GrpcPort      string                 `protobuf:"bytes,8,opt,name=grpc_port,json=grpcPort,proto3" json:"grpc_port,omitempty"` // порт gRPC сервиса мастера, пустой - менеджер обращается к мастеру по REST
+	Pull          bool                   `protobuf:"varint,9,opt,name=pull,proto3" json:"pull,omitempty"`                        // слейв сам забирает подзадачи через /subtask/next
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	mi := &file_gridpb_grid_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Node) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Node) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Node) GetPublicPort() string {
+	if x != nil {
+		return x.PublicPort
+	}
+	return ""
+}
+
+func (x *Node) GetPrivatePort() string {
+	if x != nil {
+		return x.PrivatePort
+	}
+	return ""
+}
+
+func (x *Node) GetCpu() int32 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+func (x *Node) GetSlots() int32 {
+	if x != nil {
+		return x.Slots
+	}
+	return 0
+}
+
+func (x *Node) GetBenchmark() float64 {
+	if x != nil {
+		return x.Benchmark
+	}
+	return 0
+}
+
+func (x *Node) GetGrpcPort() string {
+	if x != nil {
+		return x.GrpcPort
+	}
+	return ""
+}
+
+func (x *Node) GetPull() bool {
+	if x != nil {
+		return x.Pull
+	}
+	return false
+}
+
+type RemoveNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Drain         bool                   `protobuf:"varint,2,opt,name=drain,proto3" json:"drain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveNodeRequest) Reset() {
+	*x = RemoveNodeRequest{}
+	mi := &file_gridpb_grid_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNodeRequest) ProtoMessage() {}
+
+func (x *RemoveNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNodeRequest.ProtoReflect.Descriptor instead.
+func (*RemoveNodeRequest) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RemoveNodeRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *RemoveNodeRequest) GetDrain() bool {
+	if x != nil {
+		return x.Drain
+	}
+	return false
+}
+
+type Heartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Subtasks      []string               `protobuf:"bytes,4,rep,name=subtasks,proto3" json:"subtasks,omitempty"`
+	Load          int32                  `protobuf:"varint,5,opt,name=load,proto3" json:"load,omitempty"`
+	Version       string                 `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_gridpb_grid_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Heartbeat) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetSubtasks() []string {
+	if x != nil {
+		return x.Subtasks
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetLoad() int32 {
+	if x != nil {
+		return x.Load
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type ScriptConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Script        string                 `protobuf:"bytes,1,opt,name=script,proto3" json:"script,omitempty"`
+	FuncName      string                 `protobuf:"bytes,2,opt,name=func_name,json=funcName,proto3" json:"func_name,omitempty"`
+	Hash          string                 `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptConfig) Reset() {
+	*x = ScriptConfig{}
+	mi := &file_gridpb_grid_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptConfig) ProtoMessage() {}
+
+func (x *ScriptConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptConfig.ProtoReflect.Descriptor instead.
+func (*ScriptConfig) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ScriptConfig) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+func (x *ScriptConfig) GetFuncName() string {
+	if x != nil {
+		return x.FuncName
+	}
+	return ""
+}
+
+func (x *ScriptConfig) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type ExecutionLimits struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MaxSteps       uint64                 `protobuf:"varint,1,opt,name=max_steps,json=maxSteps,proto3" json:"max_steps,omitempty"`
+	TimeoutMs      int64                  `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	MaxResultBytes int64                  `protobuf:"varint,3,opt,name=max_result_bytes,json=maxResultBytes,proto3" json:"max_result_bytes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExecutionLimits) Reset() {
+	*x = ExecutionLimits{}
+	mi := &file_gridpb_grid_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionLimits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionLimits) ProtoMessage() {}
+
+func (x *ExecutionLimits) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionLimits.ProtoReflect.Descriptor instead.
+func (*ExecutionLimits) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExecutionLimits) GetMaxSteps() uint64 {
+	if x != nil {
+		return x.MaxSteps
+	}
+	return 0
+}
+
+func (x *ExecutionLimits) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *ExecutionLimits) GetMaxResultBytes() int64 {
+	if x != nil {
+		return x.MaxResultBytes
+	}
+	return 0
+}
+
+type TaskConfig struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MasterUuid      string                 `protobuf:"bytes,1,opt,name=master_uuid,json=masterUuid,proto3" json:"master_uuid,omitempty"`
+	GeneratorScript *ScriptConfig          `protobuf:"bytes,2,opt,name=generator_script,json=generatorScript,proto3" json:"generator_script,omitempty"`
+	ComputeScript   *ScriptConfig          `protobuf:"bytes,3,opt,name=compute_script,json=computeScript,proto3" json:"compute_script,omitempty"`
+	Data            []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"` // JSON
+	DataRef         string                 `protobuf:"bytes,5,opt,name=data_ref,json=dataRef,proto3" json:"data_ref,omitempty"`
+	Limits          *ExecutionLimits       `protobuf:"bytes,6,opt,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TaskConfig) Reset() {
+	*x = TaskConfig{}
+	mi := &file_gridpb_grid_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskConfig) ProtoMessage() {}
+
+func (x *TaskConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskConfig.ProtoReflect.Descriptor instead.
+func (*TaskConfig) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TaskConfig) GetMasterUuid() string {
+	if x != nil {
+		return x.MasterUuid
+	}
+	return ""
+}
+
+func (x *TaskConfig) GetGeneratorScript() *ScriptConfig {
+	if x != nil {
+		return x.GeneratorScript
+	}
+	return nil
+}
+
+func (x *TaskConfig) GetComputeScript() *ScriptConfig {
+	if x != nil {
+		return x.ComputeScript
+	}
+	return nil
+}
+
+func (x *TaskConfig) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *TaskConfig) GetDataRef() string {
+	if x != nil {
+		return x.DataRef
+	}
+	return ""
+}
+
+func (x *TaskConfig) GetLimits() *ExecutionLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type AddTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskUuid      string                 `protobuf:"bytes,1,opt,name=task_uuid,json=taskUuid,proto3" json:"task_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTaskResponse) Reset() {
+	*x = AddTaskResponse{}
+	mi := &file_gridpb_grid_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTaskResponse) ProtoMessage() {}
+
+func (x *AddTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTaskResponse.ProtoReflect.Descriptor instead.
+func (*AddTaskResponse) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AddTaskResponse) GetTaskUuid() string {
+	if x != nil {
+		return x.TaskUuid
+	}
+	return ""
+}
+
+type TaskRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskUuid      string                 `protobuf:"bytes,1,opt,name=task_uuid,json=taskUuid,proto3" json:"task_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskRef) Reset() {
+	*x = TaskRef{}
+	mi := &file_gridpb_grid_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskRef) ProtoMessage() {}
+
+func (x *TaskRef) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskRef.ProtoReflect.Descriptor instead.
+func (*TaskRef) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TaskRef) GetTaskUuid() string {
+	if x != nil {
+		return x.TaskUuid
+	}
+	return ""
+}
+
+type TaskStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskUuid      string                 `protobuf:"bytes,1,opt,name=task_uuid,json=taskUuid,proto3" json:"task_uuid,omitempty"`
+	MasterUuid    string                 `protobuf:"bytes,2,opt,name=master_uuid,json=masterUuid,proto3" json:"master_uuid,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Counter       uint32                 `protobuf:"varint,4,opt,name=counter,proto3" json:"counter,omitempty"`
+	InFlight      int32                  `protobuf:"varint,5,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`
+	Completed     int32                  `protobuf:"varint,6,opt,name=completed,proto3" json:"completed,omitempty"`
+	Failed        int32                  `protobuf:"varint,7,opt,name=failed,proto3" json:"failed,omitempty"`
+	Exhausted     bool                   `protobuf:"varint,8,opt,name=exhausted,proto3" json:"exhausted,omitempty"`
+	End           uint32                 `protobuf:"varint,9,opt,name=end,proto3" json:"end,omitempty"`
+	Size          *uint32                `protobuf:"varint,10,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	Progress      *float64               `protobuf:"fixed64,11,opt,name=progress,proto3,oneof" json:"progress,omitempty"`
+	Slaves        []string               `protobuf:"bytes,12,rep,name=slaves,proto3" json:"slaves,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskStatus) Reset() {
+	*x = TaskStatus{}
+	mi := &file_gridpb_grid_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStatus) ProtoMessage() {}
+
+func (x *TaskStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStatus.ProtoReflect.Descriptor instead.
+func (*TaskStatus) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TaskStatus) GetTaskUuid() string {
+	if x != nil {
+		return x.TaskUuid
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetMasterUuid() string {
+	if x != nil {
+		return x.MasterUuid
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetCounter() uint32 {
+	if x != nil {
+		return x.Counter
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetInFlight() int32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetCompleted() int32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetExhausted() bool {
+	if x != nil {
+		return x.Exhausted
+	}
+	return false
+}
+
+func (x *TaskStatus) GetEnd() uint32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetSize() uint32 {
+	if x != nil && x.Size != nil {
+		return *x.Size
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetProgress() float64 {
+	if x != nil && x.Progress != nil {
+		return *x.Progress
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetSlaves() []string {
+	if x != nil {
+		return x.Slaves
+	}
+	return nil
+}
+
+type ComputeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SubtaskUuid   string                 `protobuf:"bytes,1,opt,name=subtask_uuid,json=subtaskUuid,proto3" json:"subtask_uuid,omitempty"`
+	Generate      *ScriptConfig          `protobuf:"bytes,2,opt,name=generate,proto3" json:"generate,omitempty"`
+	Compute       *ScriptConfig          `protobuf:"bytes,3,opt,name=compute,proto3" json:"compute,omitempty"`
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"` // JSON, пустые если передан data_ref
+	DataRef       string                 `protobuf:"bytes,5,opt,name=data_ref,json=dataRef,proto3" json:"data_ref,omitempty"`
+	Amount        uint32                 `protobuf:"varint,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Start         uint32                 `protobuf:"varint,7,opt,name=start,proto3" json:"start,omitempty"`
+	Limits        *ExecutionLimits       `protobuf:"bytes,8,opt,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputeRequest) Reset() {
+	*x = ComputeRequest{}
+	mi := &file_gridpb_grid_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeRequest) ProtoMessage() {}
+
+func (x *ComputeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeRequest.ProtoReflect.Descriptor instead.
+func (*ComputeRequest) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ComputeRequest) GetSubtaskUuid() string {
+	if x != nil {
+		return x.SubtaskUuid
+	}
+	return ""
+}
+
+func (x *ComputeRequest) GetGenerate() *ScriptConfig {
+	if x != nil {
+		return x.Generate
+	}
+	return nil
+}
+
+func (x *ComputeRequest) GetCompute() *ScriptConfig {
+	if x != nil {
+		return x.Compute
+	}
+	return nil
+}
+
+func (x *ComputeRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ComputeRequest) GetDataRef() string {
+	if x != nil {
+		return x.DataRef
+	}
+	return ""
+}
+
+func (x *ComputeRequest) GetAmount() uint32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ComputeRequest) GetStart() uint32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *ComputeRequest) GetLimits() *ExecutionLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type CompleteSubtaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SlaveUuid     string                 `protobuf:"bytes,1,opt,name=slave_uuid,json=slaveUuid,proto3" json:"slave_uuid,omitempty"`
+	SubtaskUuid   string                 `protobuf:"bytes,2,opt,name=subtask_uuid,json=subtaskUuid,proto3" json:"subtask_uuid,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"` // JSON
+	FreeSlots     *int32                 `protobuf:"varint,5,opt,name=free_slots,json=freeSlots,proto3,oneof" json:"free_slots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteSubtaskRequest) Reset() {
+	*x = CompleteSubtaskRequest{}
+	mi := &file_gridpb_grid_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteSubtaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteSubtaskRequest) ProtoMessage() {}
+
+func (x *CompleteSubtaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteSubtaskRequest.ProtoReflect.Descriptor instead.
+func (*CompleteSubtaskRequest) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CompleteSubtaskRequest) GetSlaveUuid() string {
+	if x != nil {
+		return x.SlaveUuid
+	}
+	return ""
+}
+
+func (x *CompleteSubtaskRequest) GetSubtaskUuid() string {
+	if x != nil {
+		return x.SubtaskUuid
+	}
+	return ""
+}
+
+func (x *CompleteSubtaskRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CompleteSubtaskRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *CompleteSubtaskRequest) GetFreeSlots() int32 {
+	if x != nil && x.FreeSlots != nil {
+		return *x.FreeSlots
+	}
+	return 0
+}
+
+type ErrorSubtaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SlaveUuid     string                 `protobuf:"bytes,1,opt,name=slave_uuid,json=slaveUuid,proto3" json:"slave_uuid,omitempty"`
+	SubtaskUuid   string                 `protobuf:"bytes,2,opt,name=subtask_uuid,json=subtaskUuid,proto3" json:"subtask_uuid,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorSubtaskRequest) Reset() {
+	*x = ErrorSubtaskRequest{}
+	mi := &file_gridpb_grid_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorSubtaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorSubtaskRequest) ProtoMessage() {}
+
+func (x *ErrorSubtaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorSubtaskRequest.ProtoReflect.Descriptor instead.
+func (*ErrorSubtaskRequest) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ErrorSubtaskRequest) GetSlaveUuid() string {
+	if x != nil {
+		return x.SlaveUuid
+	}
+	return ""
+}
+
+func (x *ErrorSubtaskRequest) GetSubtaskUuid() string {
+	if x != nil {
+		return x.SubtaskUuid
+	}
+	return ""
+}
+
+func (x *ErrorSubtaskRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ErrorSubtaskRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BlobRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobRef) Reset() {
+	*x = BlobRef{}
+	mi := &file_gridpb_grid_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobRef) ProtoMessage() {}
+
+func (x *BlobRef) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobRef.ProtoReflect.Descriptor instead.
+func (*BlobRef) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BlobRef) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Blob struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Blob) Reset() {
+	*x = Blob{}
+	mi := &file_gridpb_grid_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Blob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Blob) ProtoMessage() {}
+
+func (x *Blob) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Blob.ProtoReflect.Descriptor instead.
+func (*Blob) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Blob) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type SubtaskResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskUuid      string                 `protobuf:"bytes,1,opt,name=task_uuid,json=taskUuid,proto3" json:"task_uuid,omitempty"`
+	SubtaskUuid   string                 `protobuf:"bytes,2,opt,name=subtask_uuid,json=subtaskUuid,proto3" json:"subtask_uuid,omitempty"`
+	Start         uint32                 `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	Amount        uint32                 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Data          []byte                 `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"` // JSON
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubtaskResult) Reset() {
+	*x = SubtaskResult{}
+	mi := &file_gridpb_grid_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubtaskResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubtaskResult) ProtoMessage() {}
+
+func (x *SubtaskResult) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubtaskResult.ProtoReflect.Descriptor instead.
+func (*SubtaskResult) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SubtaskResult) GetTaskUuid() string {
+	if x != nil {
+		return x.TaskUuid
+	}
+	return ""
+}
+
+func (x *SubtaskResult) GetSubtaskUuid() string {
+	if x != nil {
+		return x.SubtaskUuid
+	}
+	return ""
+}
+
+func (x *SubtaskResult) GetStart() uint32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *SubtaskResult) GetAmount() uint32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *SubtaskResult) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type TaskSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskUuid      string                 `protobuf:"bytes,1,opt,name=task_uuid,json=taskUuid,proto3" json:"task_uuid,omitempty"`
+	End           uint32                 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	Completed     int32                  `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`
+	Failed        int32                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	Dropped       int32                  `protobuf:"varint,5,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskSummary) Reset() {
+	*x = TaskSummary{}
+	mi := &file_gridpb_grid_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskSummary) ProtoMessage() {}
+
+func (x *TaskSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskSummary.ProtoReflect.Descriptor instead.
+func (*TaskSummary) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TaskSummary) GetTaskUuid() string {
+	if x != nil {
+		return x.TaskUuid
+	}
+	return ""
+}
+
+func (x *TaskSummary) GetEnd() uint32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *TaskSummary) GetCompleted() int32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *TaskSummary) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *TaskSummary) GetDropped() int32 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+type TaskError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskUuid      string                 `protobuf:"bytes,1,opt,name=task_uuid,json=taskUuid,proto3" json:"task_uuid,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskError) Reset() {
+	*x = TaskError{}
+	mi := &file_gridpb_grid_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskError) ProtoMessage() {}
+
+func (x *TaskError) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskError.ProtoReflect.Descriptor instead.
+func (*TaskError) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TaskError) GetTaskUuid() string {
+	if x != nil {
+		return x.TaskUuid
+	}
+	return ""
+}
+
+func (x *TaskError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ManagerMessage - сообщение менеджера слейву в потоке Dispatch. На запрос с id слейв отвечает сообщением с тем же id,
+// ack - ответ менеджера на complete и error слейва
+type ManagerMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Msg:
+	//
+	//	*ManagerMessage_Compute
+	//	*ManagerMessage_Cancel
+	//	*ManagerMessage_Status
+	//	*ManagerMessage_Ack
+	Msg           isManagerMessage_Msg `protobuf_oneof:"msg"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ManagerMessage) Reset() {
+	*x = ManagerMessage{}
+	mi := &file_gridpb_grid_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManagerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManagerMessage) ProtoMessage() {}
+
+func (x *ManagerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManagerMessage.ProtoReflect.Descriptor instead.
+func (*ManagerMessage) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ManagerMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ManagerMessage) GetMsg() isManagerMessage_Msg {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *ManagerMessage) GetCompute() *ComputeRequest {
+	if x != nil {
+		if x, ok := x.Msg.(*ManagerMessage_Compute); ok {
+			return x.Compute
+		}
+	}
+	return nil
+}
+
+func (x *ManagerMessage) GetCancel() *CancelSubtask {
+	if x != nil {
+		if x, ok := x.Msg.(*ManagerMessage_Cancel); ok {
+			return x.Cancel
+		}
+	}
+	return nil
+}
+
+func (x *ManagerMessage) GetStatus() *StatusRequest {
+	if x != nil {
+		if x, ok := x.Msg.(*ManagerMessage_Status); ok {
+			return x.Status
+		}
+	}
+	return nil
+}
+
+func (x *ManagerMessage) GetAck() *Ack {
+	if x != nil {
+		if x, ok := x.Msg.(*ManagerMessage_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+type isManagerMessage_Msg interface {
+	isManagerMessage_Msg()
+}
+
+type ManagerMessage_Compute struct {
+	Compute *ComputeRequest `protobuf:"bytes,2,opt,name=compute,proto3,oneof"`
+}
+
+type ManagerMessage_Cancel struct {
+	Cancel *CancelSubtask `protobuf:"bytes,3,opt,name=cancel,proto3,oneof"`
+}
+
+type ManagerMessage_Status struct {
+	Status *StatusRequest `protobuf:"bytes,4,opt,name=status,proto3,oneof"`
+}
+
+type ManagerMessage_Ack struct {
+	Ack *Ack `protobuf:"bytes,5,opt,name=ack,proto3,oneof"`
+}
+
+func (*ManagerMessage_Compute) isManagerMessage_Msg() {}
+
+func (*ManagerMessage_Cancel) isManagerMessage_Msg() {}
+
+func (*ManagerMessage_Status) isManagerMessage_Msg() {}
+
+func (*ManagerMessage_Ack) isManagerMessage_Msg() {}
+
+type CancelSubtask struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SubtaskUuid   string                 `protobuf:"bytes,1,opt,name=subtask_uuid,json=subtaskUuid,proto3" json:"subtask_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelSubtask) Reset() {
+	*x = CancelSubtask{}
+	mi := &file_gridpb_grid_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelSubtask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelSubtask) ProtoMessage() {}
+
+func (x *CancelSubtask) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelSubtask.ProtoReflect.Descriptor instead.
+func (*CancelSubtask) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CancelSubtask) GetSubtaskUuid() string {
+	if x != nil {
+		return x.SubtaskUuid
+	}
+	return ""
+}
+
+type StatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	mi := &file_gridpb_grid_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{20}
+}
+
+// SlaveMessage - сообщение слейва менеджеру в потоке Dispatch. Первое сообщение потока - hello
+type SlaveMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Msg:
+	//
+	//	*SlaveMessage_Hello
+	//	*SlaveMessage_Ack
+	//	*SlaveMessage_Status
+	//	*SlaveMessage_Complete
+	//	*SlaveMessage_Error
+	Msg           isSlaveMessage_Msg `protobuf_oneof:"msg"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlaveMessage) Reset() {
+	*x = SlaveMessage{}
+	mi := &file_gridpb_grid_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlaveMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlaveMessage) ProtoMessage() {}
+
+func (x *SlaveMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlaveMessage.ProtoReflect.Descriptor instead.
+func (*SlaveMessage) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SlaveMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SlaveMessage) GetMsg() isSlaveMessage_Msg {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *SlaveMessage) GetHello() *Hello {
+	if x != nil {
+		if x, ok := x.Msg.(*SlaveMessage_Hello); ok {
+			return x.Hello
+		}
+	}
+	return nil
+}
+
+func (x *SlaveMessage) GetAck() *Ack {
+	if x != nil {
+		if x, ok := x.Msg.(*SlaveMessage_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+func (x *SlaveMessage) GetStatus() *SlaveStatus {
+	if x != nil {
+		if x, ok := x.Msg.(*SlaveMessage_Status); ok {
+			return x.Status
+		}
+	}
+	return nil
+}
+
+func (x *SlaveMessage) GetComplete() *CompleteSubtaskRequest {
+	if x != nil {
+		if x, ok := x.Msg.(*SlaveMessage_Complete); ok {
+			return x.Complete
+		}
+	}
+	return nil
+}
+
+func (x *SlaveMessage) GetError() *ErrorSubtaskRequest {
+	if x != nil {
+		if x, ok := x.Msg.(*SlaveMessage_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isSlaveMessage_Msg interface {
+	isSlaveMessage_Msg()
+}
+
+type SlaveMessage_Hello struct {
+	Hello *Hello `protobuf:"bytes,2,opt,name=hello,proto3,oneof"`
+}
+
+type SlaveMessage_Ack struct {
+	Ack *Ack `protobuf:"bytes,3,opt,name=ack,proto3,oneof"`
+}
+
+type SlaveMessage_Status struct {
+	Status *SlaveStatus `protobuf:"bytes,4,opt,name=status,proto3,oneof"`
+}
+
+type SlaveMessage_Complete struct {
+	Complete *CompleteSubtaskRequest `protobuf:"bytes,5,opt,name=complete,proto3,oneof"`
+}
+
+type SlaveMessage_Error struct {
+	Error *ErrorSubtaskRequest `protobuf:"bytes,6,opt,name=error,proto3,oneof"`
+}
+
+func (*SlaveMessage_Hello) isSlaveMessage_Msg() {}
+
+func (*SlaveMessage_Ack) isSlaveMessage_Msg() {}
+
+func (*SlaveMessage_Status) isSlaveMessage_Msg() {}
+
+func (*SlaveMessage_Complete) isSlaveMessage_Msg() {}
+
+func (*SlaveMessage_Error) isSlaveMessage_Msg() {}
+
+type Hello struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SlaveUuid     string                 `protobuf:"bytes,1,opt,name=slave_uuid,json=slaveUuid,proto3" json:"slave_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hello) Reset() {
+	*x = Hello{}
+	mi := &file_gridpb_grid_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hello) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hello) ProtoMessage() {}
+
+func (x *Hello) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hello.ProtoReflect.Descriptor instead.
+func (*Hello) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Hello) GetSlaveUuid() string {
+	if x != nil {
+		return x.SlaveUuid
+	}
+	return ""
+}
+
+// Ack - ответ на запрос в потоке Dispatch. Пустой code - запрос выполнен
+type Ack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ack) Reset() {
+	*x = Ack{}
+	mi := &file_gridpb_grid_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ack) ProtoMessage() {}
+
+func (x *Ack) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ack.ProtoReflect.Descriptor instead.
+func (*Ack) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Ack) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Ack) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SlaveStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlaveStatus) Reset() {
+	*x = SlaveStatus{}
+	mi := &file_gridpb_grid_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlaveStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlaveStatus) ProtoMessage() {}
+
+func (x *SlaveStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_gridpb_grid_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlaveStatus.ProtoReflect.Descriptor instead.
+func (*SlaveStatus) Descriptor() ([]byte, []int) {
+	return file_gridpb_grid_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SlaveStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+var File_gridpb_grid_proto protoreflect.FileDescriptor
+
+const file_gridpb_grid_proto_rawDesc = "" +
+	"\n" +
+	"\x11gridpb/grid.proto\x12\agrid.v1\"\a\n" +
+	"\x05Empty\"\xe7\x01\n" +
+	"\x04Node\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1f\n" +
+	"\vpublic_port\x18\x03 \x01(\tR\n" +
+	"publicPort\x12!\n" +
+	"\fprivate_port\x18\x04 \x01(\tR\vprivatePort\x12\x10\n" +
+	"\x03cpu\x18\x05 \x01(\x05R\x03cpu\x12\x14\n" +
+	"\x05slots\x18\x06 \x01(\x05R\x05slots\x12\x1c\n" +
+	"\tbenchmark\x18\a \x01(\x01R\tbenchmark\x12\x1b\n" +
+	"\tgrpc_port\x18\b \x01(\tR\bgrpcPort\x12\x12\n" +
+	"\x04pull\x18\t \x01(\bR\x04pull\"=\n" +
+	"\x11RemoveNodeRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
+	"\x05drain\x18\x02 \x01(\bR\x05drain\"\x95\x01\n" +
+	"\tHeartbeat\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1a\n" +
+	"\bsubtasks\x18\x04 \x03(\tR\bsubtasks\x12\x12\n" +
+	"\x04load\x18\x05 \x01(\x05R\x04load\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\tR\aversion\"W\n" +
+	"\fScriptConfig\x12\x16\n" +
+	"\x06script\x18\x01 \x01(\tR\x06script\x12\x1b\n" +
+	"\tfunc_name\x18\x02 \x01(\tR\bfuncName\x12\x12\n" +
+	"\x04hash\x18\x03 \x01(\tR\x04hash\"w\n" +
+	"\x0fExecutionLimits\x12\x1b\n" +
+	"\tmax_steps\x18\x01 \x01(\x04R\bmaxSteps\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x02 \x01(\x03R\ttimeoutMs\x12(\n" +
+	"\x10max_result_bytes\x18\x03 \x01(\x03R\x0emaxResultBytes\"\x8e\x02\n" +
+	"\n" +
+	"TaskConfig\x12\x1f\n" +
+	"\vmaster_uuid\x18\x01 \x01(\tR\n" +
+	"masterUuid\x12@\n" +
+	"\x10generator_script\x18\x02 \x01(\v2\x15.grid.v1.ScriptConfigR\x0fgeneratorScript\x12<\n" +
+	"\x0ecompute_script\x18\x03 \x01(\v2\x15.grid.v1.ScriptConfigR\rcomputeScript\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x12\x19\n" +
+	"\bdata_ref\x18\x05 \x01(\tR\adataRef\x120\n" +
+	"\x06limits\x18\x06 \x01(\v2\x18.grid.v1.ExecutionLimitsR\x06limits\".\n" +
+	"\x0fAddTaskResponse\x12\x1b\n" +
+	"\ttask_uuid\x18\x01 \x01(\tR\btaskUuid\"&\n" +
+	"\aTaskRef\x12\x1b\n" +
+	"\ttask_uuid\x18\x01 \x01(\tR\btaskUuid\"\xe7\x02\n" +
+	"\n" +
+	"TaskStatus\x12\x1b\n" +
+	"\ttask_uuid\x18\x01 \x01(\tR\btaskUuid\x12\x1f\n" +
+	"\vmaster_uuid\x18\x02 \x01(\tR\n" +
+	"masterUuid\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x18\n" +
+	"\acounter\x18\x04 \x01(\rR\acounter\x12\x1b\n" +
+	"\tin_flight\x18\x05 \x01(\x05R\binFlight\x12\x1c\n" +
+	"\tcompleted\x18\x06 \x01(\x05R\tcompleted\x12\x16\n" +
+	"\x06failed\x18\a \x01(\x05R\x06failed\x12\x1c\n" +
+	"\texhausted\x18\b \x01(\bR\texhausted\x12\x10\n" +
+	"\x03end\x18\t \x01(\rR\x03end\x12\x17\n" +
+	"\x04size\x18\n" +
+	" \x01(\rH\x00R\x04size\x88\x01\x01\x12\x1f\n" +
+	"\bprogress\x18\v \x01(\x01H\x01R\bprogress\x88\x01\x01\x12\x16\n" +
+	"\x06slaves\x18\f \x03(\tR\x06slavesB\a\n" +
+	"\x05_sizeB\v\n" +
+	"\t_progress\"\xa6\x02\n" +
+	"\x0eComputeRequest\x12!\n" +
+	"\fsubtask_uuid\x18\x01 \x01(\tR\vsubtaskUuid\x121\n" +
+	"\bgenerate\x18\x02 \x01(\v2\x15.grid.v1.ScriptConfigR\bgenerate\x12/\n" +
+	"\acompute\x18\x03 \x01(\v2\x15.grid.v1.ScriptConfigR\acompute\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x12\x19\n" +
+	"\bdata_ref\x18\x05 \x01(\tR\adataRef\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\rR\x06amount\x12\x14\n" +
+	"\x05start\x18\a \x01(\rR\x05start\x120\n" +
+	"\x06limits\x18\b \x01(\v2\x18.grid.v1.ExecutionLimitsR\x06limits\"\xb9\x01\n" +
+	"\x16CompleteSubtaskRequest\x12\x1d\n" +
+	"\n" +
+	"slave_uuid\x18\x01 \x01(\tR\tslaveUuid\x12!\n" +
+	"\fsubtask_uuid\x18\x02 \x01(\tR\vsubtaskUuid\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x12\"\n" +
+	"\n" +
+	"free_slots\x18\x05 \x01(\x05H\x00R\tfreeSlots\x88\x01\x01B\r\n" +
+	"\v_free_slots\"\x81\x01\n" +
+	"\x13ErrorSubtaskRequest\x12\x1d\n" +
+	"\n" +
+	"slave_uuid\x18\x01 \x01(\tR\tslaveUuid\x12!\n" +
+	"\fsubtask_uuid\x18\x02 \x01(\tR\vsubtaskUuid\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\x19\n" +
+	"\aBlobRef\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1a\n" +
+	"\x04Blob\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\x91\x01\n" +
+	"\rSubtaskResult\x12\x1b\n" +
+	"\ttask_uuid\x18\x01 \x01(\tR\btaskUuid\x12!\n" +
+	"\fsubtask_uuid\x18\x02 \x01(\tR\vsubtaskUuid\x12\x14\n" +
+	"\x05start\x18\x03 \x01(\rR\x05start\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\rR\x06amount\x12\x12\n" +
+	"\x04data\x18\x05 \x01(\fR\x04data\"\x8c\x01\n" +
+	"\vTaskSummary\x12\x1b\n" +
+	"\ttask_uuid\x18\x01 \x01(\tR\btaskUuid\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\rR\x03end\x12\x1c\n" +
+	"\tcompleted\x18\x03 \x01(\x05R\tcompleted\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\x12\x18\n" +
+	"\adropped\x18\x05 \x01(\x05R\adropped\">\n" +
+	"\tTaskError\x12\x1b\n" +
+	"\ttask_uuid\x18\x01 \x01(\tR\btaskUuid\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xe2\x01\n" +
+	"\x0eManagerMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x123\n" +
+	"\acompute\x18\x02 \x01(\v2\x17.grid.v1.ComputeRequestH\x00R\acompute\x120\n" +
+	"\x06cancel\x18\x03 \x01(\v2\x16.grid.v1.CancelSubtaskH\x00R\x06cancel\x120\n" +
+	"\x06status\x18\x04 \x01(\v2\x16.grid.v1.StatusRequestH\x00R\x06status\x12 \n" +
+	"\x03ack\x18\x05 \x01(\v2\f.grid.v1.AckH\x00R\x03ackB\x05\n" +
+	"\x03msg\"2\n" +
+	"\rCancelSubtask\x12!\n" +
+	"\fsubtask_uuid\x18\x01 \x01(\tR\vsubtaskUuid\"\x0f\n" +
+	"\rStatusRequest\"\x94\x02\n" +
+	"\fSlaveMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12&\n" +
+	"\x05hello\x18\x02 \x01(\v2\x0e.grid.v1.HelloH\x00R\x05hello\x12 \n" +
+	"\x03ack\x18\x03 \x01(\v2\f.grid.v1.AckH\x00R\x03ack\x12.\n" +
+	"\x06status\x18\x04 \x01(\v2\x14.grid.v1.SlaveStatusH\x00R\x06status\x12=\n" +
+	"\bcomplete\x18\x05 \x01(\v2\x1f.grid.v1.CompleteSubtaskRequestH\x00R\bcomplete\x124\n" +
+	"\x05error\x18\x06 \x01(\v2\x1c.grid.v1.ErrorSubtaskRequestH\x00R\x05errorB\x05\n" +
+	"\x03msg\"&\n" +
+	"\x05Hello\x12\x1d\n" +
+	"\n" +
+	"slave_uuid\x18\x01 \x01(\tR\tslaveUuid\"/\n" +
+	"\x03Ack\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"%\n" +
+	"\vSlaveStatus\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status2\xe1\x04\n" +
+	"\aManager\x12/\n" +
+	"\x0eRegisterMaster\x12\r.grid.v1.Node\x1a\x0e.grid.v1.Empty\x12.\n" +
+	"\rRegisterSlave\x12\r.grid.v1.Node\x1a\x0e.grid.v1.Empty\x128\n" +
+	"\n" +
+	"RemoveNode\x12\x1a.grid.v1.RemoveNodeRequest\x1a\x0e.grid.v1.Empty\x12/\n" +
+	"\tHeartbeat\x12\x12.grid.v1.Heartbeat\x1a\x0e.grid.v1.Empty\x128\n" +
+	"\aAddTask\x12\x13.grid.v1.TaskConfig\x1a\x18.grid.v1.AddTaskResponse\x12-\n" +
+	"\tCloseTask\x12\x10.grid.v1.TaskRef\x1a\x0e.grid.v1.Empty\x123\n" +
+	"\n" +
+	"TaskStatus\x12\x10.grid.v1.TaskRef\x1a\x13.grid.v1.TaskStatus\x12B\n" +
+	"\x0fCompleteSubtask\x12\x1f.grid.v1.CompleteSubtaskRequest\x1a\x0e.grid.v1.Empty\x12<\n" +
+	"\fErrorSubtask\x12\x1c.grid.v1.ErrorSubtaskRequest\x1a\x0e.grid.v1.Empty\x12*\n" +
+	"\aGetBlob\x12\x10.grid.v1.BlobRef\x1a\r.grid.v1.Blob\x12>\n" +
+	"\bDispatch\x12\x15.grid.v1.SlaveMessage\x1a\x17.grid.v1.ManagerMessage(\x010\x012\xa2\x01\n" +
+	"\x06Master\x125\n" +
+	"\vSubtaskDone\x12\x16.grid.v1.SubtaskResult\x1a\x0e.grid.v1.Empty\x120\n" +
+	"\bTaskDone\x12\x14.grid.v1.TaskSummary\x1a\x0e.grid.v1.Empty\x12/\n" +
+	"\tTaskError\x12\x12.grid.v1.TaskError\x1a\x0e.grid.v1.EmptyB\x11Z\x0fprotocol/gridpbb\x06proto3"
+
+var (
+	file_gridpb_grid_proto_rawDescOnce sync.Once
+	file_gridpb_grid_proto_rawDescData []byte
+)
+
+func file_gridpb_grid_proto_rawDescGZIP() []byte {
+	file_gridpb_grid_proto_rawDescOnce.Do(func() {
+		file_gridpb_grid_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gridpb_grid_proto_rawDesc), len(file_gridpb_grid_proto_rawDesc)))
+	})
+	return file_gridpb_grid_proto_rawDescData
+}
+
+var file_gridpb_grid_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_gridpb_grid_proto_goTypes = []any{
+	(*Empty)(nil),                  // 0: grid.v1.Empty
+	(*Node)(nil),                   // 1: grid.v1.Node
+	(*RemoveNodeRequest)(nil),      // 2: grid.v1.RemoveNodeRequest
+	(*Heartbeat)(nil),              // 3: grid.v1.Heartbeat
+	(*ScriptConfig)(nil),           // 4: grid.v1.ScriptConfig
+	(*ExecutionLimits)(nil),        // 5: grid.v1.ExecutionLimits
+	(*TaskConfig)(nil),             // 6: grid.v1.TaskConfig
+	(*AddTaskResponse)(nil),        // 7: grid.v1.AddTaskResponse
+	(*TaskRef)(nil),                // 8: grid.v1.TaskRef
+	(*TaskStatus)(nil),             // 9: grid.v1.TaskStatus
+	(*ComputeRequest)(nil),         // 10: grid.v1.ComputeRequest
+	(*CompleteSubtaskRequest)(nil), // 11: grid.v1.CompleteSubtaskRequest
+	(*ErrorSubtaskRequest)(nil),    // 12: grid.v1.ErrorSubtaskRequest
+	(*BlobRef)(nil),                // 13: grid.v1.BlobRef
+	(*Blob)(nil),                   // 14: grid.v1.Blob
+	(*SubtaskResult)(nil),          // 15: grid.v1.SubtaskResult
+	(*TaskSummary)(nil),            // 16: grid.v1.TaskSummary
+	(*TaskError)(nil),              // 17: grid.v1.TaskError
+	(*ManagerMessage)(nil),         // 18: grid.v1.ManagerMessage
+	(*CancelSubtask)(nil),          // 19: grid.v1.CancelSubtask
+	(*StatusRequest)(nil),          // 20: grid.v1.StatusRequest
+	(*SlaveMessage)(nil),           // 21: grid.v1.SlaveMessage
+	(*Hello)(nil),                  // 22: grid.v1.Hello
+	(*Ack)(nil),                    // 23: grid.v1.Ack
+	(*SlaveStatus)(nil),            // 24: grid.v1.SlaveStatus
+}
+var file_gridpb_grid_proto_depIdxs = []int32{
+	4,  // 0: grid.v1.TaskConfig.generator_script:type_name -> grid.v1.ScriptConfig
+	4,  // 1: grid.v1.TaskConfig.compute_script:type_name -> grid.v1.ScriptConfig
+	5,  // 2: grid.v1.TaskConfig.limits:type_name -> grid.v1.ExecutionLimits
+	4,  // 3: grid.v1.ComputeRequest.generate:type_name -> grid.v1.ScriptConfig
+	4,  // 4: grid.v1.ComputeRequest.compute:type_name -> grid.v1.ScriptConfig
+	5,  // 5: grid.v1.ComputeRequest.limits:type_name -> grid.v1.ExecutionLimits
+	10, // 6: grid.v1.ManagerMessage.compute:type_name -> grid.v1.ComputeRequest
+	19, // 7: grid.v1.ManagerMessage.cancel:type_name -> grid.v1.CancelSubtask
+	20, // 8: grid.v1.ManagerMessage.status:type_name -> grid.v1.StatusRequest
+	23, // 9: grid.v1.ManagerMessage.ack:type_name -> grid.v1.Ack
+	22, // 10: grid.v1.SlaveMessage.hello:type_name -> grid.v1.Hello
+	23, // 11: grid.v1.SlaveMessage.ack:type_name -> grid.v1.Ack
+	24, // 12: grid.v1.SlaveMessage.status:type_name -> grid.v1.SlaveStatus
+	11, // 13: grid.v1.SlaveMessage.complete:type_name -> grid.v1.CompleteSubtaskRequest
+	12, // 14: grid.v1.SlaveMessage.error:type_name -> grid.v1.ErrorSubtaskRequest
+	1,  // 15: grid.v1.Manager.RegisterMaster:input_type -> grid.v1.Node
+	1,  // 16: grid.v1.Manager.RegisterSlave:input_type -> grid.v1.Node
+	2,  // 17: grid.v1.Manager.RemoveNode:input_type -> grid.v1.RemoveNodeRequest
+	3,  // 18: grid.v1.Manager.Heartbeat:input_type -> grid.v1.Heartbeat
+	6,  // 19: grid.v1.Manager.AddTask:input_type -> grid.v1.TaskConfig
+	8,  // 20: grid.v1.Manager.CloseTask:input_type -> grid.v1.TaskRef
+	8,  // 21: grid.v1.Manager.TaskStatus:input_type -> grid.v1.TaskRef
+	11, // 22: grid.v1.Manager.CompleteSubtask:input_type -> grid.v1.CompleteSubtaskRequest
+	12, // 23: grid.v1.Manager.ErrorSubtask:input_type -> grid.v1.ErrorSubtaskRequest
+	13, // 24: grid.v1.Manager.GetBlob:input_type -> grid.v1.BlobRef
+	21, // 25: grid.v1.Manager.Dispatch:input_type -> grid.v1.SlaveMessage
+	15, // 26: grid.v1.Master.SubtaskDone:input_type -> grid.v1.SubtaskResult
+	16, // 27: grid.v1.Master.TaskDone:input_type -> grid.v1.TaskSummary
+	17, // 28: grid.v1.Master.TaskError:input_type -> grid.v1.TaskError
+	0,  // 29: grid.v1.Manager.RegisterMaster:output_type -> grid.v1.Empty
+	0,  // 30: grid.v1.Manager.RegisterSlave:output_type -> grid.v1.Empty
+	0,  // 31: grid.v1.Manager.RemoveNode:output_type -> grid.v1.Empty
+	0,  // 32: grid.v1.Manager.Heartbeat:output_type -> grid.v1.Empty
+	7,  // 33: grid.v1.Manager.AddTask:output_type -> grid.v1.AddTaskResponse
+	0,  // 34: grid.v1.Manager.CloseTask:output_type -> grid.v1.Empty
+	9,  // 35: grid.v1.Manager.TaskStatus:output_type -> grid.v1.TaskStatus
+	0,  // 36: grid.v1.Manager.CompleteSubtask:output_type -> grid.v1.Empty
+	0,  // 37: grid.v1.Manager.ErrorSubtask:output_type -> grid.v1.Empty
+	14, // 38: grid.v1.Manager.GetBlob:output_type -> grid.v1.Blob
+	18, // 39: grid.v1.Manager.Dispatch:output_type -> grid.v1.ManagerMessage
+	0,  // 40: grid.v1.Master.SubtaskDone:output_type -> grid.v1.Empty
+	0,  // 41: grid.v1.Master.TaskDone:output_type -> grid.v1.Empty
+	0,  // 42: grid.v1.Master.TaskError:output_type -> grid.v1.Empty
+	29, // [29:43] is the sub-list for method output_type
+	15, // [15:29] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_gridpb_grid_proto_init() }
+func file_gridpb_grid_proto_init() {
+	if File_gridpb_grid_proto != nil {
+		return
+	}
+	file_gridpb_grid_proto_msgTypes[9].OneofWrappers = []any{}
+	file_gridpb_grid_proto_msgTypes[11].OneofWrappers = []any{}
+	file_gridpb_grid_proto_msgTypes[18].OneofWrappers = []any{
+		(*ManagerMessage_Compute)(nil),
+		(*ManagerMessage_Cancel)(nil),
+		(*ManagerMessage_Status)(nil),
+		(*ManagerMessage_Ack)(nil),
+	}
+	file_gridpb_grid_proto_msgTypes[21].OneofWrappers = []any{
+		(*SlaveMessage_Hello)(nil),
+		(*SlaveMessage_Ack)(nil),
+		(*SlaveMessage_Status)(nil),
+		(*SlaveMessage_Complete)(nil),
+		(*SlaveMessage_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gridpb_grid_proto_rawDesc), len(file_gridpb_grid_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_gridpb_grid_proto_goTypes,
+		DependencyIndexes: file_gridpb_grid_proto_depIdxs,
+		MessageInfos:      file_gridpb_grid_proto_msgTypes,
+	}.Build()
+	File_gridpb_grid_proto = out.File
+	file_gridpb_grid_proto_goTypes = nil
+	file_gridpb_grid_proto_depIdxs = nil
+}
@@ -0,0 +1,690 @@
+// gRPC транспорт между нодами. Сообщения повторяют JSON типы пакета protocol,
+// данные задач и результаты подзадач передаются как JSON в bytes.
+//
+// Сгенерированный код обновляется из каталога protocol:
+//   protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative gridpb/grid.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: gridpb/grid.proto
+
+package gridpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Manager_RegisterMaster_FullMethodName  = "/grid.v1.Manager/RegisterMaster"
+	Manager_RegisterSlave_FullMethodName   = "/grid.v1.Manager/RegisterSlave"
+	Manager_RemoveNode_FullMethodName      = "/grid.v1.Manager/RemoveNode"
+	Manager_Heartbeat_FullMethodName       = "/grid.v1.Manager/Heartbeat"
+	Manager_AddTask_FullMethodName         = "/grid.v1.Manager/AddTask"
+	Manager_CloseTask_FullMethodName       = "/grid.v1.Manager/CloseTask"
+	Manager_TaskStatus_FullMethodName      = "/grid.v1.Manager/TaskStatus"
+	Manager_CompleteSubtask_FullMethodName = "/grid.v1.Manager/CompleteSubtask"
+	Manager_ErrorSubtask_FullMethodName    = "/grid.v1.Manager/ErrorSubtask"
+	Manager_GetBlob_FullMethodName         = "/grid.v1.Manager/GetBlob"
+	Manager_Dispatch_FullMethodName        = "/grid.v1.Manager/Dispatch"
+)
+
+// ManagerClient is the client API for Manager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Manager - API менеджера для мастеров и слейвов
+type ManagerClient interface {
+	RegisterMaster(ctx context.Context, in *Node, opts ...grpc.CallOption) (*Empty, error)
+	RegisterSlave(ctx context.Context, in *Node, opts ...grpc.CallOption) (*Empty, error)
+	RemoveNode(ctx context.Context, in *RemoveNodeRequest, opts ...grpc.CallOption) (*Empty, error)
+	Heartbeat(ctx context.Context, in *Heartbeat, opts ...grpc.CallOption) (*Empty, error)
+	AddTask(ctx context.Context, in *TaskConfig, opts ...grpc.CallOption) (*AddTaskResponse, error)
+	CloseTask(ctx context.Context, in *TaskRef, opts ...grpc.CallOption) (*Empty, error)
+	TaskStatus(ctx context.Context, in *TaskRef, opts ...grpc.CallOption) (*TaskStatus, error)
+	CompleteSubtask(ctx context.Context, in *CompleteSubtaskRequest, opts ...grpc.CallOption) (*Empty, error)
+	ErrorSubtask(ctx context.Context, in *ErrorSubtaskRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetBlob(ctx context.Context, in *BlobRef, opts ...grpc.CallOption) (*Blob, error)
+	// Dispatch - поток между менеджером и слейвом: подзадачи, отмена и статус от менеджера, результаты и ошибки от слейва
+	Dispatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SlaveMessage, ManagerMessage], error)
+}
+
+type managerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewManagerClient(cc grpc.ClientConnInterface) ManagerClient {
+	return &managerClient{cc}
+}
+
+func (c *managerClient) RegisterMaster(ctx context.Context, in *Node, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Manager_RegisterMaster_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) RegisterSlave(ctx context.Context, in *Node, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Manager_RegisterSlave_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) RemoveNode(ctx context.Context, in *RemoveNodeRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Manager_RemoveNode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) Heartbeat(ctx context.Context, in *Heartbeat, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Manager_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) AddTask(ctx context.Context, in *TaskConfig, opts ...grpc.CallOption) (*AddTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddTaskResponse)
+	err := c.cc.Invoke(ctx, Manager_AddTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) CloseTask(ctx context.Context, in *TaskRef, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Manager_CloseTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) TaskStatus(ctx context.Context, in *TaskRef, opts ...grpc.CallOption) (*TaskStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskStatus)
+	err := c.cc.Invoke(ctx, Manager_TaskStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) CompleteSubtask(ctx context.Context, in *CompleteSubtaskRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Manager_CompleteSubtask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) ErrorSubtask(ctx context.Context, in *ErrorSubtaskRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Manager_ErrorSubtask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) GetBlob(ctx context.Context, in *BlobRef, opts ...grpc.CallOption) (*Blob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Blob)
+	err := c.cc.Invoke(ctx, Manager_GetBlob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managerClient) Dispatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SlaveMessage, ManagerMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Manager_ServiceDesc.Streams[0], Manager_Dispatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SlaveMessage, ManagerMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Manager_DispatchClient = grpc.BidiStreamingClient[SlaveMessage, ManagerMessage]
+
+// ManagerServer is the server API for Manager service.
+// All implementations must embed UnimplementedManagerServer
+// for forward compatibility.
+//
+// Manager - API менеджера для мастеров и слейвов
+type ManagerServer interface {
+	RegisterMaster(context.Context, *Node) (*Empty, error)
+	RegisterSlave(context.Context, *Node) (*Empty, error)
+	RemoveNode(context.Context, *RemoveNodeRequest) (*Empty, error)
+	Heartbeat(context.Context, *Heartbeat) (*Empty, error)
+	AddTask(context.Context, *TaskConfig) (*AddTaskResponse, error)
+	CloseTask(context.Context, *TaskRef) (*Empty, error)
+	TaskStatus(context.Context, *TaskRef) (*TaskStatus, error)
+	CompleteSubtask(context.Context, *CompleteSubtaskRequest) (*Empty, error)
+	ErrorSubtask(context.Context, *ErrorSubtaskRequest) (*Empty, error)
+	GetBlob(context.Context, *BlobRef) (*Blob, error)
+	// Dispatch - поток между менеджером и слейвом: подзадачи, отмена и статус от менеджера, результаты и ошибки от слейва
+	Dispatch(grpc.BidiStreamingServer[SlaveMessage, ManagerMessage]) error
+	mustEmbedUnimplementedManagerServer()
+}
+
+// UnimplementedManagerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedManagerServer struct{}
+
+func (UnimplementedManagerServer) RegisterMaster(context.Context, *Node) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterMaster not implemented")
+}
+func (UnimplementedManagerServer) RegisterSlave(context.Context, *Node) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterSlave not implemented")
+}
+func (UnimplementedManagerServer) RemoveNode(context.Context, *RemoveNodeRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveNode not implemented")
+}
+func (UnimplementedManagerServer) Heartbeat(context.Context, *Heartbeat) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedManagerServer) AddTask(context.Context, *TaskConfig) (*AddTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTask not implemented")
+}
+func (UnimplementedManagerServer) CloseTask(context.Context, *TaskRef) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseTask not implemented")
+}
+func (UnimplementedManagerServer) TaskStatus(context.Context, *TaskRef) (*TaskStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TaskStatus not implemented")
+}
+func (UnimplementedManagerServer) CompleteSubtask(context.Context, *CompleteSubtaskRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteSubtask not implemented")
+}
+func (UnimplementedManagerServer) ErrorSubtask(context.Context, *ErrorSubtaskRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ErrorSubtask not implemented")
+}
+func (UnimplementedManagerServer) GetBlob(context.Context, *BlobRef) (*Blob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlob not implemented")
+}
+func (UnimplementedManagerServer) Dispatch(grpc.BidiStreamingServer[SlaveMessage, ManagerMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method Dispatch not implemented")
+}
+func (UnimplementedManagerServer) mustEmbedUnimplementedManagerServer() {}
+func (UnimplementedManagerServer) testEmbeddedByValue()                 {}
+
+// UnsafeManagerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ManagerServer will
+// result in compilation errors.
+type UnsafeManagerServer interface {
+	mustEmbedUnimplementedManagerServer()
+}
+
+func RegisterManagerServer(s grpc.ServiceRegistrar, srv ManagerServer) {
+	// If the following call pancis, it indicates UnimplementedManagerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Manager_ServiceDesc, srv)
+}
+
+func _Manager_RegisterMaster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Node)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).RegisterMaster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_RegisterMaster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).RegisterMaster(ctx, req.(*Node))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_RegisterSlave_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Node)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).RegisterSlave(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_RegisterSlave_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).RegisterSlave(ctx, req.(*Node))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_RemoveNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).RemoveNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_RemoveNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).RemoveNode(ctx, req.(*RemoveNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Heartbeat)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).Heartbeat(ctx, req.(*Heartbeat))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_AddTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).AddTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_AddTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).AddTask(ctx, req.(*TaskConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_CloseTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).CloseTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_CloseTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).CloseTask(ctx, req.(*TaskRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_TaskStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).TaskStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_TaskStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).TaskStatus(ctx, req.(*TaskRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_CompleteSubtask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteSubtaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).CompleteSubtask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_CompleteSubtask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).CompleteSubtask(ctx, req.(*CompleteSubtaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_ErrorSubtask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ErrorSubtaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).ErrorSubtask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_ErrorSubtask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).ErrorSubtask(ctx, req.(*ErrorSubtaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_GetBlob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlobRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagerServer).GetBlob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Manager_GetBlob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagerServer).GetBlob(ctx, req.(*BlobRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Manager_Dispatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ManagerServer).Dispatch(&grpc.GenericServerStream[SlaveMessage, ManagerMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Manager_DispatchServer = grpc.BidiStreamingServer[SlaveMessage, ManagerMessage]
+
+// Manager_ServiceDesc is the grpc.ServiceDesc for Manager service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Manager_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grid.v1.Manager",
+	HandlerType: (*ManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterMaster",
+			Handler:    _Manager_RegisterMaster_Handler,
+		},
+		{
+			MethodName: "RegisterSlave",
+			Handler:    _Manager_RegisterSlave_Handler,
+		},
+		{
+			MethodName: "RemoveNode",
+			Handler:    _Manager_RemoveNode_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _Manager_Heartbeat_Handler,
+		},
+		{
+			MethodName: "AddTask",
+			Handler:    _Manager_AddTask_Handler,
+		},
+		{
+			MethodName: "CloseTask",
+			Handler:    _Manager_CloseTask_Handler,
+		},
+		{
+			MethodName: "TaskStatus",
+			Handler:    _Manager_TaskStatus_Handler,
+		},
+		{
+			MethodName: "CompleteSubtask",
+			Handler:    _Manager_CompleteSubtask_Handler,
+		},
+		{
+			MethodName: "ErrorSubtask",
+			Handler:    _Manager_ErrorSubtask_Handler,
+		},
+		{
+			MethodName: "GetBlob",
+			Handler:    _Manager_GetBlob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Dispatch",
+			Handler:       _Manager_Dispatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "gridpb/grid.proto",
+}
+
+const (
+	Master_SubtaskDone_FullMethodName = "/grid.v1.Master/SubtaskDone"
+	Master_TaskDone_FullMethodName    = "/grid.v1.Master/TaskDone"
+	Master_TaskError_FullMethodName   = "/grid.v1.Master/TaskError"
+)
+
+// MasterClient is the client API for Master service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Master - API мастера для менеджера
+type MasterClient interface {
+	SubtaskDone(ctx context.Context, in *SubtaskResult, opts ...grpc.CallOption) (*Empty, error)
+	TaskDone(ctx context.Context, in *TaskSummary, opts ...grpc.CallOption) (*Empty, error)
+	TaskError(ctx context.Context, in *TaskError, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type masterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMasterClient(cc grpc.ClientConnInterface) MasterClient {
+	return &masterClient{cc}
+}
+
+func (c *masterClient) SubtaskDone(ctx context.Context, in *SubtaskResult, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Master_SubtaskDone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) TaskDone(ctx context.Context, in *TaskSummary, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Master_TaskDone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) TaskError(ctx context.Context, in *TaskError, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Master_TaskError_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MasterServer is the server API for Master service.
+// All implementations must embed UnimplementedMasterServer
+// for forward compatibility.
+//
+// Master - API мастера для менеджера
+type MasterServer interface {
+	SubtaskDone(context.Context, *SubtaskResult) (*Empty, error)
+	TaskDone(context.Context, *TaskSummary) (*Empty, error)
+	TaskError(context.Context, *TaskError) (*Empty, error)
+	mustEmbedUnimplementedMasterServer()
+}
+
+// UnimplementedMasterServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMasterServer struct{}
+
+func (UnimplementedMasterServer) SubtaskDone(context.Context, *SubtaskResult) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubtaskDone not implemented")
+}
+func (UnimplementedMasterServer) TaskDone(context.Context, *TaskSummary) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TaskDone not implemented")
+}
+func (UnimplementedMasterServer) TaskError(context.Context, *TaskError) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TaskError not implemented")
+}
+func (UnimplementedMasterServer) mustEmbedUnimplementedMasterServer() {}
+func (UnimplementedMasterServer) testEmbeddedByValue()                {}
+
+// UnsafeMasterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MasterServer will
+// result in compilation errors.
+type UnsafeMasterServer interface {
+	mustEmbedUnimplementedMasterServer()
+}
+
+func RegisterMasterServer(s grpc.ServiceRegistrar, srv MasterServer) {
+	// If the following call pancis, it indicates UnimplementedMasterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Master_ServiceDesc, srv)
+}
+
+func _Master_SubtaskDone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubtaskResult)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).SubtaskDone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_SubtaskDone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).SubtaskDone(ctx, req.(*SubtaskResult))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_TaskDone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskSummary)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).TaskDone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_TaskDone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).TaskDone(ctx, req.(*TaskSummary))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_TaskError_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskError)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).TaskError(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_TaskError_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).TaskError(ctx, req.(*TaskError))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Master_ServiceDesc is the grpc.ServiceDesc for Master service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Master_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grid.v1.Master",
+	HandlerType: (*MasterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubtaskDone",
+			Handler:    _Master_SubtaskDone_Handler,
+		},
+		{
+			MethodName: "TaskDone",
+			Handler:    _Master_TaskDone_Handler,
+		},
+		{
+			MethodName: "TaskError",
+			Handler:    _Master_TaskError_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gridpb/grid.proto",
+}
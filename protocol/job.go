@@ -0,0 +1,84 @@
+package protocol
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobScript - скрипт задачи: код целиком или путь к файлу в каталоге скриптов мастера
+type JobScript struct {
+	Script   string `json:"Script,omitempty"`
+	Path     string `json:"Path,omitempty"`
+	FuncName string `json:"FuncName,omitempty"`
+}
+
+// JobRequest - постановка задачи на мастер (/job/add). Пустые поля берутся из конфига мастера
+type JobRequest struct {
+	Name            string          `json:"Name,omitempty"`
+	Engine          string          `json:"Engine,omitempty"` // движок обработки результатов подзадач
+	GeneratorScript JobScript       `json:"GeneratorScript"`
+	ComputeScript   JobScript       `json:"ComputeScript"`
+	ReduceScript    JobScript       `json:"ReduceScript"` // для движка reduce: reduce(acc, partial) на мастере
+	Reducer         ReducerOptions  `json:"Reducer"`      // для встроенных движков свертки
+	Sink            SinkOptions     `json:"Sink"`         // для движка jsonl
+	Data            json.RawMessage `json:"Data"`
+	Limits          ExecutionLimits `json:"Limits"`
+}
+
+// ReducerOptions - настройки встроенной свертки (min, max, sum, count, concat, topk, histogram)
+type ReducerOptions struct {
+	Key       string `json:"Key,omitempty"`       // путь к значению в результате подзадачи, например data.cost
+	K         int    `json:"K,omitempty"`         // для topk
+	Ascending bool   `json:"Ascending,omitempty"` // для topk: K наименьших вместо K наибольших
+}
+
+// SinkOptions - настройки движка jsonl, который сохраняет результаты подзадач на диск
+type SinkOptions struct {
+	Path string `json:"Path,omitempty"` // путь внутри каталога результатов мастера, по умолчанию - по скриптам и данным задачи
+	Dir  bool   `json:"Dir,omitempty"`  // результат каждой подзадачи в отдельном файле каталога Path
+}
+
+type AddJobResp struct {
+	JobID    string `json:"JobID"`
+	TaskUUID string `json:"TaskUUID"`
+}
+
+// JobInfo - состояние задачи на мастере
+type JobInfo struct {
+	JobID     string       `json:"JobID"`
+	Name      string       `json:"Name,omitempty"`
+	Engine    string       `json:"Engine"`
+	TaskUUID  string       `json:"TaskUUID"`
+	Status    string       `json:"Status"`
+	Error     string       `json:"Error,omitempty"`
+	CreatedAt time.Time    `json:"CreatedAt"`
+	Coverage  Coverage     `json:"Coverage"`
+	Summary   *TaskSummary `json:"Summary,omitempty"` // итог от менеджера, когда задача решена
+}
+
+// Coverage - принятые мастером результаты подзадач
+type Coverage struct {
+	Subtasks   int     `json:"Subtasks"`   // принятые подзадачи
+	Duplicates int     `json:"Duplicates"` // отброшенные повторы
+	Covered    uint64  `json:"Covered"`    // сумма покрытых диапазонов
+	Prefix     uint64  `json:"Prefix"`     // конец непрерывного покрытия от 0
+	Ranges     []Range `json:"Ranges"`
+}
+
+// Range - полуинтервал [Start, End)
+type Range struct {
+	Start uint64 `json:"Start"`
+	End   uint64 `json:"End"`
+}
+
+// JobResult - результат задачи (/job/result)
+type JobResult struct {
+	JobInfo
+	Result interface{} `json:"Result"`
+}
+
+// JobStatus - состояние задачи на мастере и на менеджере (/job/status)
+type JobStatus struct {
+	JobInfo
+	Manager *TaskStatus `json:"Manager,omitempty"`
+}
@@ -0,0 +1,49 @@
+package protocol
+
+import "time"
+
+// Node - регистрация ноды на менеджере (/node/register/master, /node/register/slave)
+type Node struct {
+	UUID        string  `json:"UUID"`
+	Url         string  `json:"Url"` // адрес ноды, пустой - менеджер берет адрес, с которого пришла регистрация
+	PublicPort  string  `json:"PublicPort"`
+	PrivatePort string  `json:"PrivatePort"`
+	CPU         int     `json:"CPU,omitempty"`       // кол-во ядер слейва
+	Slots       int     `json:"Slots,omitempty"`     // кол-во подзадач, которые слейв решает параллельно
+	Benchmark   float64 `json:"Benchmark,omitempty"` // результат эталонного Starlark скрипта на слейве, тыс. итераций/сек
+	GrpcPort    string  `json:"GrpcPort,omitempty"`  // порт gRPC сервиса мастера, пустой - менеджер обращается к мастеру по REST
+	Pull        bool    `json:"Pull,omitempty"`      // слейв сам забирает подзадачи через /subtask/next, менеджер их ему не отправляет
+}
+
+// типы нод в Heartbeat и NodeInfo
+const (
+	NODE_KIND_MASTER = "master"
+	NODE_KIND_SLAVE  = "slave"
+)
+
+// Heartbeat - периодический сигнал жизни ноды (/node/heartbeat)
+type Heartbeat struct {
+	UUID     string   `json:"UUID"`
+	Kind     string   `json:"Kind"`               // master/slave
+	Status   string   `json:"Status"`             // статус ноды, для слейва - как в /checkStatus
+	Subtasks []string `json:"Subtasks,omitempty"` // uuid подзадач, которые сейчас решает слейв
+	Load     int      `json:"Load"`               // занятые слоты слейва или решаемые задачи мастера
+	Version  string   `json:"Version,omitempty"`
+}
+
+// NodeInfo - нода в /node/list
+type NodeInfo struct {
+	Kind        string    `json:"Kind"` // master/slave
+	UUID        string    `json:"UUID"`
+	Url         string    `json:"Url"`
+	PublicPort  string    `json:"PublicPort"`
+	Status      string    `json:"Status,omitempty"`
+	Slots       int       `json:"Slots,omitempty"`
+	FreeSlots   int       `json:"FreeSlots,omitempty"`
+	Power       uint32    `json:"Power,omitempty"`
+	Benchmark   float64   `json:"Benchmark,omitempty"`
+	ActiveTasks int       `json:"ActiveTasks,omitempty"`
+	Load        int       `json:"Load"` // из последнего heartbeat
+	Version     string    `json:"Version,omitempty"`
+	LastSeen    time.Time `json:"LastSeen"`
+}
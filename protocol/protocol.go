@@ -0,0 +1,69 @@
+/*
+Package protocol - общий протокол менеджера, мастеров и слейвов: типы запросов и ответов, пути API
+и типизированные клиенты (пакет protocol/client)
+
+Модуль подключается всеми тремя нодами через replace на ../protocol. Несовместимое изменение формата
+на проводе - новая мажорная версия: Version и префикс API меняются вместе
+*/
+package protocol
+
+// Version - версия протокола, модуль тегается как protocol/vX.Y.Z
+const Version = "1.0.0"
+
+// API_V1 - префикс публичного API всех нод
+const API_V1 = "/api/v1"
+
+// режим получения подзадач слейвом (DISPATCH_MODE в конфиге слейва)
+const (
+	DISPATCH_PUSH = "push" // менеджер отправляет подзадачи на публичный порт слейва
+	DISPATCH_PULL = "pull" // слейв сам забирает подзадачи у менеджера длинным опросом /subtask/next
+)
+
+// транспорт запросов ноды к менеджеру (TRANSPORT в конфиге мастера и слейва)
+const (
+	TRANSPORT_HTTP = "http" // REST API
+	TRANSPORT_GRPC = "grpc" // gRPC сервис из protocol/gridpb, слейв получает подзадачи через поток Dispatch
+)
+
+// пути API менеджера
+const (
+	MANAGER_REGISTER_MASTER_PATH  = "/node/register/master"
+	MANAGER_REGISTER_SLAVE_PATH   = "/node/register/slave"
+	MANAGER_REMOVE_NODE_PATH      = "/node/remove"
+	MANAGER_HEARTBEAT_PATH        = "/node/heartbeat"
+	MANAGER_LIST_NODES_PATH       = "/node/list"
+	MANAGER_ADD_TASK_PATH         = "/task/add"
+	MANAGER_CLOSE_TASK_PATH       = "/task/close"
+	MANAGER_TASK_STATUS_PATH      = "/task/status"
+	MANAGER_LIST_TASKS_PATH       = "/task/list"
+	MANAGER_COMPLETE_SUBTASK_PATH = "/subtask/complete"
+	MANAGER_ERROR_SUBTASK_PATH    = "/subtask/error"
+	MANAGER_NEXT_SUBTASK_PATH     = "/subtask/next"
+	MANAGER_BLOB_PATH             = "/blob"
+)
+
+// пути API слейва
+const (
+	SLAVE_ADD_TASK_PATH     = "/addTask"
+	SLAVE_CHECK_STATUS_PATH = "/checkStatus"
+	SLAVE_CANCEL_PATH       = "/cancel"
+)
+
+// пути API мастера, вызываемые менеджером
+const (
+	MASTER_SUBTASK_DONE_PATH = "/subtask/done"
+	MASTER_TASK_DONE_PATH    = "/task/done"
+	MASTER_TASK_ERROR_PATH   = "/task/error"
+)
+
+// пути API задач мастера для клиентов (gridctl), id задачи - в параметре id
+const (
+	MASTER_JOB_ADD_PATH    = "/job/add"
+	MASTER_JOB_LIST_PATH   = "/job/list"
+	MASTER_JOB_STATUS_PATH = "/job/status"
+	MASTER_JOB_RESULT_PATH = "/job/result"
+	MASTER_JOB_CANCEL_PATH = "/job/cancel"
+)
+
+// HEALTH_PATH - проверка жизни на приватном порту любой ноды
+const HEALTH_PATH = "/health"
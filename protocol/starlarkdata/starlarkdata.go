@@ -0,0 +1,150 @@
+/*
+Package starlarkdata - перевод данных задачи между JSON и Starlark
+
+Общий для менеджера, который пробно запускает скрипты при постановке задачи, и слейва, который их решает:
+входные данные и результаты скриптов должны разбираться на обеих нодах одинаково
+*/
+package starlarkdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.starlark.net/starlark"
+)
+
+// ParseInput - входные данные задачи в Starlark значение: все числа - float, пустые данные - None
+func ParseInput(data json.RawMessage) (starlark.Value, error) {
+	if len(data) == 0 {
+		return starlark.None, nil
+	}
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return ToValue(raw)
+}
+
+// ToValue - значение, разобранное encoding/json, в Starlark значение
+func ToValue(v interface{}) (starlark.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(v), nil
+	case int:
+		return starlark.MakeInt(v), nil
+	case float64:
+		return starlark.Float(v), nil
+	case string:
+		return starlark.String(v), nil
+	case []interface{}:
+		elems := make([]starlark.Value, len(v))
+		for i, elem := range v {
+			val, err := ToValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = val
+		}
+		return starlark.NewList(elems), nil
+	case map[string]interface{}:
+		dict := starlark.NewDict(len(v))
+		for key, val := range v {
+			sv, err := ToValue(val)
+			if err != nil {
+				return nil, err
+			}
+			if err := dict.SetKey(starlark.String(key), sv); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %T", v)
+	}
+}
+
+// ToGo - Starlark значение в Go значение для JSON результата подзадачи
+func ToGo(v starlark.Value) (interface{}, error) {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.Int:
+		if i, ok := v.Int64(); ok {
+			return i, nil
+		}
+		return nil, errors.New("integer out of range")
+	case starlark.Float:
+		return float64(v), nil
+	case starlark.String:
+		return string(v), nil
+	case *starlark.List:
+		var result []interface{}
+		iter := v.Iterate()
+		defer iter.Done()
+		var elem starlark.Value
+		for iter.Next(&elem) {
+			goVal, err := ToGo(elem)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, goVal)
+		}
+		return result, nil
+	case *starlark.Dict:
+		result := make(map[string]interface{})
+		for _, key := range v.Keys() {
+			keyStr, ok := key.(starlark.String)
+			if !ok {
+				return nil, errors.New("non-string key in dict")
+			}
+			val, _, _ := v.Get(key)
+			goVal, err := ToGo(val)
+			if err != nil {
+				return nil, err
+			}
+			result[string(keyStr)] = goVal
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported Starlark type: %T", v)
+	}
+}
+
+/*
+StatusAndData - статус и данные результата generate или compute
+
+Результат - (status, data) или {"status": ..., "data": ...}, любое другое значение - данные со статусом "ok"
+*/
+func StatusAndData(v starlark.Value) (string, starlark.Value, error) {
+	var status, data starlark.Value
+	switch v := v.(type) {
+	case starlark.Tuple:
+		if v.Len() != 2 {
+			return "", nil, fmt.Errorf("expected tuple of length 2, got %d", v.Len())
+		}
+		status, data = v.Index(0), v.Index(1)
+	case *starlark.Dict:
+		var found bool
+		if status, found, _ = v.Get(starlark.String("status")); !found {
+			return "", nil, errors.New("status not found in result dict")
+		}
+		if data, found, _ = v.Get(starlark.String("data")); !found {
+			return "", nil, errors.New("data not found in result dict")
+		}
+	default:
+		return "ok", v, nil
+	}
+
+	s, ok := status.(starlark.String)
+	if !ok {
+		return "", nil, fmt.Errorf("status is %s, not a string", status.Type())
+	}
+
+	return string(s), data, nil
+}
@@ -0,0 +1,77 @@
+package protocol
+
+import "encoding/json"
+
+// ComputeRequest - подзадача от менеджера слейву (/addTask)
+type ComputeRequest struct {
+	UuidSubtask string          `json:"UuidSubtask"`
+	Generate    ScriptConfig    `json:"GenerateScript"`    // скрипт генерации подзадач из данных
+	Compute     ScriptConfig    `json:"ComputeScript"`     // скрипт решения подзадач
+	Data        json.RawMessage `json:"Data,omitempty"`    // данные из которых нужно генерировать, пустые если передан DataRef
+	DataRef     string          `json:"DataRef,omitempty"` // id блоба с данными, если Data не передана
+	Amount      uint32          `json:"Amount"`            // кол-во подзадач которое нужно сгенерировать
+	Start       uint32          `json:"Start"`             // позиция от которой генерировать данные для просчета
+	Limits      ExecutionLimits `json:"Limits"`            // лимиты выполнения скриптов на слейве
+}
+
+// CompleteSubtaskRequest - результат подзадачи от слейва (/subtask/complete)
+type CompleteSubtaskRequest struct {
+	SlaveUUID   string          `json:"SlaveUUID"`
+	SubtaskUUID string          `json:"SubtaskUUID"`
+	Status      string          `json:"Status"`
+	Data        json.RawMessage `json:"Data"`
+	FreeSlots   *int            `json:"FreeSlots,omitempty"` // свободные слоты слейва после этой подзадачи
+}
+
+// UnmarshalJSON - слейвы до протокола 1.0.0 присылали свой uuid в поле "UUID", поэтому менеджер обновляется первым
+func (r *CompleteSubtaskRequest) UnmarshalJSON(data []byte) error {
+	type plain CompleteSubtaskRequest
+	req := struct {
+		*plain
+		LegacyUUID string `json:"UUID"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+	if r.SlaveUUID == "" {
+		r.SlaveUUID = req.LegacyUUID
+	}
+
+	return nil
+}
+
+// типы ошибок подзадачи, которые слейв отправляет менеджеру в /subtask/error
+const (
+	ERROR_SCRIPT        = "script"            // ошибка в скрипте или входных данных
+	ERROR_LIMIT_STEPS   = "limit_steps"       // превышен лимит шагов Starlark
+	ERROR_LIMIT_TIMEOUT = "limit_timeout"     // превышен лимит времени на подзадачу
+	ERROR_LIMIT_RESULT  = "limit_result_size" // превышен размер результата
+	ERROR_REJECTED      = "rejected"          // слейв в режиме pull не смог принять выданную ему подзадачу
+)
+
+// ErrorSubtaskRequest - ошибка решения подзадачи от слейва (/subtask/error)
+type ErrorSubtaskRequest struct {
+	SlaveUUID   string `json:"SlaveUUID"`
+	SubtaskUUID string `json:"SubtaskUUID"`
+	Type        string `json:"Type"` // тип ошибки: script/limit_steps/limit_timeout/limit_result_size
+	Error       string `json:"Error"`
+}
+
+/*
+NextSubtaskResp - ответ менеджера на длинный опрос слейва в режиме pull (/subtask/next)
+
+До слейва в режиме pull менеджер не достучится, поэтому отмены его подзадач отдаются в этом же ответе
+*/
+type NextSubtaskResp struct {
+	Subtask *ComputeRequest `json:"Subtask,omitempty"` // выданная подзадача, nil - работы для слейва нет
+	Cancel  []string        `json:"Cancel,omitempty"`  // uuid подзадач слейва, решение которых нужно прекратить
+}
+
+// SubtaskResult - решенная подзадача от менеджера мастеру (/subtask/done)
+type SubtaskResult struct {
+	TaskUUID    string          `json:"TaskUUID"`
+	SubtaskUUID string          `json:"SubtaskUUID"`
+	Start       uint32          `json:"Start"`  // начало диапазона подзадачи
+	Amount      uint32          `json:"Amount"` // размер диапазона
+	Data        json.RawMessage `json:"Data"`
+}
@@ -0,0 +1,118 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCompleteSubtaskRequestRoundTrip(t *testing.T) {
+	free := 3
+	want := CompleteSubtaskRequest{
+		SlaveUUID:   "slave",
+		SubtaskUUID: "subtask",
+		Status:      "ok",
+		Data:        json.RawMessage(`{"best":[1,2,3]}`),
+		FreeSlots:   &free,
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got CompleteSubtaskRequest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestCompleteSubtaskRequestLegacyUUID(t *testing.T) {
+	var got CompleteSubtaskRequest
+	if err := json.Unmarshal([]byte(`{"UUID":"old-slave","SubtaskUUID":"subtask","Status":"ok","Data":null}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.SlaveUUID != "old-slave" {
+		t.Fatalf("SlaveUUID = %q, want old-slave", got.SlaveUUID)
+	}
+
+	// новое поле важнее старого
+	if err := json.Unmarshal([]byte(`{"UUID":"old","SlaveUUID":"new"}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.SlaveUUID != "new" {
+		t.Fatalf("SlaveUUID = %q, want new", got.SlaveUUID)
+	}
+}
+
+func TestWireTypesRoundTrip(t *testing.T) {
+	size := uint32(24)
+	progress := 50.0
+	tests := []struct {
+		name string
+		in   any
+		out  any
+	}{
+		{"ComputeRequest", &ComputeRequest{
+			UuidSubtask: "subtask",
+			Generate:    ScriptConfig{Script: "def gen(): pass", FuncName: "gen", Hash: "h1"},
+			Compute:     ScriptConfig{FuncName: "compute", Hash: "h2"},
+			DataRef:     "blob",
+			Amount:      10,
+			Start:       20,
+			Limits:      ExecutionLimits{MaxSteps: 1000, TimeoutMs: 500, MaxResultBytes: 1 << 10},
+		}, &ComputeRequest{}},
+		{"ErrorSubtaskRequest", &ErrorSubtaskRequest{SlaveUUID: "slave", SubtaskUUID: "subtask", Type: ERROR_LIMIT_STEPS, Error: "too many steps"}, &ErrorSubtaskRequest{}},
+		{"SubtaskResult", &SubtaskResult{TaskUUID: "task", SubtaskUUID: "subtask", Start: 5, Amount: 5, Data: json.RawMessage(`[1,2]`)}, &SubtaskResult{}},
+		{"TaskConfig", &TaskConfig{
+			MasterUUID:      "master",
+			GeneratorScript: ScriptConfig{Script: "x", FuncName: "gen"},
+			ComputeScript:   ScriptConfig{Script: "y", FuncName: "compute"},
+			Data:            json.RawMessage(`{"n":4}`),
+			Limits:          ExecutionLimits{MaxSteps: 7},
+		}, &TaskConfig{}},
+		{"TaskStatus", &TaskStatus{
+			TaskUUID: "task", MasterUUID: "master", Status: "solving", Counter: 12, InFlight: 2, Completed: 3,
+			Size: &size, Progress: &progress, Slaves: []string{"a", "b"},
+		}, &TaskStatus{}},
+		{"TaskSummary", &TaskSummary{TaskUUID: "task", End: 24, Completed: 6, Failed: 1, Dropped: 2}, &TaskSummary{}},
+		{"Node", &Node{UUID: "slave", PublicPort: ":8083", CPU: 4, Slots: 2, Benchmark: 1.5, GrpcPort: ":8092", Pull: true}, &Node{}},
+		{"Heartbeat", &Heartbeat{UUID: "slave", Kind: NODE_KIND_SLAVE, Status: "busy", Subtasks: []string{"s1"}, Load: 1, Version: "1.0.0"}, &Heartbeat{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(data, tt.out); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.out, tt.in) {
+				t.Fatalf("got %+v, want %+v", tt.out, tt.in)
+			}
+		})
+	}
+}
+
+func TestScriptErrorRoundTrip(t *testing.T) {
+	want := &ScriptError{Script: "compute", Stage: "syntax", Msg: "got newline", Line: 3, Col: 7}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &ScriptError{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if *got != *want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if got.Error() != "compute script: syntax error at 3:7: got newline" {
+		t.Fatalf("Error() = %q", got.Error())
+	}
+}
@@ -0,0 +1,79 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type ScriptConfig struct {
+	Script   string `json:"Script"`         // код скрипта, пустой если передан только id блоба
+	FuncName string `json:"FuncName"`       // имя функции которую нужно вызвать
+	Hash     string `json:"Hash,omitempty"` // id блоба со скриптом (sha256 кода)
+}
+
+// ExecutionLimits - лимиты выполнения скриптов подзадачи, нулевые значения - по умолчанию слейва
+type ExecutionLimits struct {
+	MaxSteps       uint64 `json:"MaxSteps,omitempty"`       // лимит шагов Starlark для каждого скрипта
+	TimeoutMs      int64  `json:"TimeoutMs,omitempty"`      // лимит времени на всю подзадачу, мс
+	MaxResultBytes int    `json:"MaxResultBytes,omitempty"` // лимит размера результата в JSON, байт
+}
+
+// TaskConfig - постановка задачи мастером (/task/add)
+type TaskConfig struct {
+	MasterUUID      string          `json:"MasterUUID"`
+	GeneratorScript ScriptConfig    `json:"GeneratorScript"`
+	ComputeScript   ScriptConfig    `json:"ComputeScript"`
+	Data            json.RawMessage `json:"Data"`
+	DataRef         string          `json:"DataRef,omitempty"` // id загруженного блоба вместо Data
+	Limits          ExecutionLimits `json:"Limits"`            // лимиты выполнения скриптов на слейвах
+}
+
+type AddTaskResp struct {
+	TaskUUID string `json:"TaskUUID"`
+}
+
+// BlobResp - id загруженного блоба (POST /blob)
+type BlobResp struct {
+	ID string `json:"ID"`
+}
+
+// TaskStatus - состояние задачи на менеджере (/task/status, /task/list)
+type TaskStatus struct {
+	TaskUUID   string   `json:"TaskUUID"`
+	MasterUUID string   `json:"MasterUUID"`
+	Status     string   `json:"Status"`             // wait/solving/error/done
+	Counter    uint32   `json:"Counter"`            // позиция, до которой розданы подзадачи
+	InFlight   int      `json:"InFlight"`           // подзадачи в работе у слейвов или в ожидании повторной отправки
+	Completed  int      `json:"Completed"`          // решенные подзадачи
+	Failed     int      `json:"Failed"`             // неудачные попытки решения подзадач
+	Exhausted  bool     `json:"Exhausted"`          // генератор сообщил об окончании данных
+	End        uint32   `json:"End"`                // позиция окончания данных, если Exhausted
+	Size       *uint32  `json:"Size,omitempty"`     // размер задачи из size(input_data)
+	Progress   *float64 `json:"Progress,omitempty"` // доля решенных элементов, %, если размер известен
+	Slaves     []string `json:"Slaves"`             // слейвы, на которых сейчас есть подзадачи задачи
+}
+
+// TaskSummary - итог задачи, менеджер отправляет его мастеру вместе с /task/done
+type TaskSummary struct {
+	TaskUUID  string `json:"TaskUUID"`
+	End       uint32 `json:"End"`       // позиция, на которой генератор сообщил об окончании данных
+	Completed int    `json:"Completed"` // решенные подзадачи
+	Failed    int    `json:"Failed"`    // неудачные попытки решения подзадач
+	Dropped   int    `json:"Dropped"`   // снятые подзадачи за концом данных
+}
+
+// ScriptError - задача отклонена при проверке скриптов на менеджере (/task/add отвечает 422)
+type ScriptError struct {
+	Script string `json:"Script"`         // generator, compute или input
+	Stage  string `json:"Stage"`          // parse, syntax, resolve, exec, signature, dry_run, size
+	Msg    string `json:"Msg"`            // текст ошибки
+	Line   int32  `json:"Line,omitempty"` // позиция в скрипте, если известна
+	Col    int32  `json:"Col,omitempty"`
+}
+
+func (e *ScriptError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s script: %s error at %d:%d: %s", e.Script, e.Stage, e.Line, e.Col, e.Msg)
+	}
+	return fmt.Sprintf("%s script: %s error: %s", e.Script, e.Stage, e.Msg)
+}
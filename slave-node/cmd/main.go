@@ -1,19 +1,18 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
 	"os/signal"
+	"protocol"
+	"protocol/client"
+	"runtime"
 	"slave-node/internal/config"
+	"slave-node/internal/dispatch"
 	"slave-node/internal/generator"
+	"slave-node/internal/pull"
 	"slave-node/internal/server"
-	"slave-node/pkg/model"
 	"syscall"
 	"time"
 )
@@ -27,18 +26,54 @@ func main() {
 	cfg := config.LoadConfig()
 	// ====================
 
+	// ===== Manager =====
+	manager, err := client.NewManagerAPI(cfg.Transport, cfg.ManagerURL, cfg.ManagerGrpcAddr, 0)
+	if err != nil {
+		log.Fatalln("[MANAGER][ERROR]:", err)
+	}
+	// по gRPC подзадачи приходят потоком Dispatch, в него же уходят результаты
+	var results generator.Manager = manager
+	var stream *dispatch.Stream
+	if grpcManager, ok := manager.(*client.GRPCManager); ok {
+		stream = dispatch.New(cfg, grpcManager)
+		results = stream
+	}
+	// =====================
+
 	// ===== Generator =====
 	log.Println("[SERVICE] INITIALIZING GENERATOR")
-	g := generator.NewGenerator(cfg)
+	g := generator.NewGenerator(cfg, results)
 
 	// =====================
 
 	// ===== Register Node =====
 	log.Println("[SERVICE] REGISTERING NODE")
-	err := registerNode(cfg)
+	err = registerNode(manager, cfg, g)
 	if err != nil {
 		log.Println(err)
 	}
+
+	// если регистрация не прошла или менеджер забыл слейв, он регистрируется заново по ответу на heartbeat
+	ctxHeartbeat, stopHeartbeat := context.WithCancel(context.Background())
+	hbManager, err := client.NewManagerAPI(cfg.Transport, cfg.ManagerURL, cfg.ManagerGrpcAddr, cfg.HeartbeatInterval)
+	if err != nil {
+		log.Println("[HEARTBEAT][ERROR]", err)
+	} else {
+		go client.RunHeartbeat(ctxHeartbeat, hbManager, cfg.HeartbeatInterval, g.Heartbeat, func() error {
+			if g.Draining() {
+				return nil
+			}
+			return registerNode(manager, cfg, g)
+		})
+	}
+
+	if stream != nil {
+		go stream.Run(ctxHeartbeat, g)
+	}
+	// в режиме pull слейв сам забирает подзадачи, менеджер их ему не отправляет
+	if cfg.Pull() {
+		go pull.Run(ctxHeartbeat, cfg, client.NewManager(cfg.ManagerURL, cfg.PullTimeout), g)
+	}
 	// =====================
 
 	// ====== Server ======
@@ -49,6 +84,24 @@ func main() {
 	// ====================
 
 	<-stop
+
+	// ===== Drain =====
+	// менеджер перестает слать подзадачи, принятые дорешиваются, после чего слейв снимается с учета
+	log.Println("[SERVICE] DRAINING NODE")
+	if err = removeNode(manager, cfg, true); err != nil {
+		log.Println("[DRAIN][ERROR]", err)
+	}
+	ctxDrain, cancelDrain := context.WithTimeout(context.Background(), cfg.DrainTimeout)
+	if err = g.Drain(ctxDrain); err != nil {
+		log.Println("[DRAIN][ERROR] subtasks are not finished:", err)
+	}
+	cancelDrain()
+	stopHeartbeat()
+	if err = removeNode(manager, cfg, false); err != nil {
+		log.Println("[DRAIN][ERROR]", err)
+	}
+	// =====================
+
 	ctxClose, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 	err = srv.Stop(ctxClose)
@@ -58,36 +111,23 @@ func main() {
 
 }
 
-func registerNode(cfg *config.Config) error {
+func registerNode(manager client.ManagerAPI, cfg *config.Config, g *generator.Generator) error {
 
-	node := model.Node{
+	node := protocol.Node{
 		UUID:        cfg.UUID,
-		Url:         "localhost", //todo
+		Url:         cfg.AdvertiseAddr,
 		PublicPort:  cfg.PublicPort,
 		PrivatePort: cfg.PrivatePort,
+		CPU:         runtime.NumCPU(),
+		Slots:       g.Slots(),
+		Benchmark:   generator.Benchmark(),
+		Pull:        cfg.Pull(),
 	}
 
-	payload, err := json.Marshal(node)
-
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", cfg.ManagerURL, cfg.ManagerRegPath), bytes.NewReader(payload))
-	if err != nil {
-		return err
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode/100 != 2 {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
-
-		return fmt.Errorf("request failed: %s", string(body))
-	}
+	return manager.RegisterSlave(node)
+}
 
-	return nil
+// removeNode - снятие слейва с учета на менеджере. С drain менеджер только перестает слать ему подзадачи
+func removeNode(manager client.ManagerAPI, cfg *config.Config, drain bool) error {
+	return manager.RemoveNode(cfg.UUID, drain)
 }
@@ -5,15 +5,45 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 	"log"
+	"protocol"
+	"time"
 )
 
+// Version - версия слейва в heartbeat, задается при сборке: -ldflags "-X slave-node/internal/config.Version=..."
+var Version = "dev"
+
 type Config struct {
-	UUID           string `json:"UUID"`
-	ManagerURL     string `envconfig:"MANAGER_URL" required:"true"`
-	ManagerRegPath string `envconfig:"MANAGER_REG_PATH" required:"true"`
+	UUID       string `json:"UUID"`
+	ManagerURL string `envconfig:"MANAGER_URL" required:"true"` // пути API менеджера - из protocol
+
+	// транспорт к менеджеру: http - REST, grpc - gRPC по MANAGER_GRPC_ADDR, подзадачи приходят потоком Dispatch
+	Transport       string `envconfig:"TRANSPORT" default:"http"`
+	ManagerGrpcAddr string `envconfig:"MANAGER_GRPC_ADDR"` // "host:port" gRPC сервиса менеджера
+
+	// получение подзадач по http: pull - слейв сам забирает их длинным опросом (работает за NAT), push - менеджер шлет их на PUBLIC_PORT.
+	// С TRANSPORT=grpc подзадачи всегда приходят потоком Dispatch
+	DispatchMode string        `envconfig:"DISPATCH_MODE" default:"push"`
+	PullTimeout  time.Duration `envconfig:"PULL_TIMEOUT" default:"40s"` // таймаут длинного опроса /subtask/next, менеджер отвечает не позже 3/4 от него
+
+	ManagerTimeout time.Duration `envconfig:"MANAGER_TIMEOUT" default:"30s"` // сколько слейв ждет ответа менеджера на результат подзадачи в потоке Dispatch
+
+	DrainTimeout      time.Duration `envconfig:"DRAIN_TIMEOUT" default:"5m"`       // сколько при остановке дорешиваются принятые подзадачи
+	HeartbeatInterval time.Duration `envconfig:"HEARTBEAT_INTERVAL" default:"10s"` // как часто слейв сообщает менеджеру, что он жив
 
 	PublicPort  string `envconfig:"PUBLIC_PORT" required:"true"`
 	PrivatePort string `envconfig:"PRIVATE_PORT" required:"true"`
+	// адрес, по которому менеджер обращается к ноде. Пусто - адрес интерфейса, через который идут запросы к менеджеру
+	AdvertiseAddr string `envconfig:"ADVERTISE_ADDR"`
+
+	Workers int `envconfig:"WORKERS"` // кол-во параллельных Starlark воркеров, 0 - по числу ядер
+
+	BlobCacheSize  int   `envconfig:"BLOB_CACHE_SIZE" default:"64"`         // кол-во блобов (скриптов и данных задач) в кэше
+	BlobCacheBytes int64 `envconfig:"BLOB_CACHE_BYTES" default:"268435456"` // суммарный размер блобов в кэше, давно не используемые вытесняются
+
+	// лимиты выполнения по умолчанию, если они не пришли в подзадаче
+	MaxExecutionSteps uint64        `envconfig:"MAX_EXECUTION_STEPS" default:"1000000000"`
+	ExecutionTimeout  time.Duration `envconfig:"EXECUTION_TIMEOUT" default:"5m"`
+	MaxResultBytes    int           `envconfig:"MAX_RESULT_BYTES" default:"10485760"`
 }
 
 func LoadConfig() *Config {
@@ -32,21 +62,59 @@ func LoadConfig() *Config {
 		log.Fatalln("[CONFIG][ERROR]:", err)
 	}
 
+	switch cfg.Transport {
+	case protocol.TRANSPORT_HTTP:
+	case protocol.TRANSPORT_GRPC:
+		if cfg.ManagerGrpcAddr == "" {
+			log.Fatalln("[CONFIG][ERROR]: MANAGER_GRPC_ADDR is required for TRANSPORT=grpc")
+		}
+	default:
+		log.Fatalf("[CONFIG][ERROR]: unknown TRANSPORT %q\n", cfg.Transport)
+	}
+	if cfg.DispatchMode != protocol.DISPATCH_PULL && cfg.DispatchMode != protocol.DISPATCH_PUSH {
+		log.Fatalf("[CONFIG][ERROR]: unknown DISPATCH_MODE %q\n", cfg.DispatchMode)
+	}
+
 	cfg.UUID = uuid.NewString()
+	if cfg.AdvertiseAddr == "" {
+		// не определился - менеджер возьмет адрес, с которого пришла регистрация
+		cfg.AdvertiseAddr = protocol.OutboundAddr(cfg.ManagerURL)
+	}
 
 	cfg.PrintConfig()
 
 	return &cfg
 }
 
+// Pull - слейв сам забирает подзадачи у менеджера через /subtask/next
+func (c *Config) Pull() bool {
+	return c.Transport == protocol.TRANSPORT_HTTP && c.DispatchMode == protocol.DISPATCH_PULL
+}
+
 func (c *Config) PrintConfig() {
 	log.Println("===================== CONFIG =====================")
 	log.Println("UUID.......................... ", c.UUID)
+	log.Println("VERSION....................... ", Version)
 	log.Println("_____________MASTER____________ ")
 	log.Println("MASTER_URL.................... ", c.ManagerURL)
+	log.Println("TRANSPORT...................... ", c.Transport)
+	log.Println("MANAGER_GRPC_ADDR.............. ", c.ManagerGrpcAddr)
+	log.Println("DISPATCH_MODE.................. ", c.DispatchMode)
+	log.Println("PULL_TIMEOUT................... ", c.PullTimeout)
+	log.Println("MANAGER_TIMEOUT................ ", c.ManagerTimeout)
+	log.Println("HEARTBEAT_INTERVAL............. ", c.HeartbeatInterval)
 	log.Println("_____________SERVER____________ ")
 	log.Println("PUBLIC_PORT.................... ", c.PublicPort)
 	log.Println("PRIVATE_PORT.................... ", c.PrivatePort)
+	log.Println("ADVERTISE_ADDR................. ", c.AdvertiseAddr)
+	log.Println("DRAIN_TIMEOUT.................. ", c.DrainTimeout)
+	log.Println("_____________GENERATOR_________ ")
+	log.Println("WORKERS........................ ", c.Workers)
+	log.Println("BLOB_CACHE_SIZE................ ", c.BlobCacheSize)
+	log.Println("BLOB_CACHE_BYTES............... ", c.BlobCacheBytes)
+	log.Println("MAX_EXECUTION_STEPS............ ", c.MaxExecutionSteps)
+	log.Println("EXECUTION_TIMEOUT.............. ", c.ExecutionTimeout)
+	log.Println("MAX_RESULT_BYTES............... ", c.MaxResultBytes)
 
 	log.Println("==================================================")
 }
@@ -0,0 +1,244 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"log"
+	"protocol"
+	"protocol/client"
+	"protocol/gridpb"
+	"slave-node/internal/config"
+	"slave-node/internal/generator"
+	"sync"
+	"time"
+)
+
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
+
+var (
+	errRequestTimeout  = errors.New("dispatch request timeout")
+	errUnexpectedReply = errors.New("unexpected dispatch reply")
+	errStreamClosed    = errors.New("dispatch stream closed")
+)
+
+/*
+Stream - поток Dispatch слейва с TRANSPORT=grpc
+
+Менеджер присылает в поток подзадачи, отмену и запрос статуса, слейв отвечает в него же и туда же
+отправляет результаты и ошибки подзадач. Пока потока нет, результаты уходят обычными вызовами gRPC,
+а менеджер шлет подзадачи по REST. Оборванный поток переподключается с нарастающей паузой
+*/
+type Stream struct {
+	cfg     *config.Config
+	manager *client.GRPCManager
+
+	mu   sync.Mutex
+	conn *conn // текущее соединение, nil - потока нет
+}
+
+func New(cfg *config.Config, manager *client.GRPCManager) *Stream {
+	return &Stream{cfg: cfg, manager: manager}
+}
+
+// Run - поддержание потока, пока не отменен ctx
+func (s *Stream) Run(ctx context.Context, g *generator.Generator) {
+	backoff := minBackoff
+	for {
+		started := time.Now()
+		err := s.serve(ctx, g)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > maxBackoff {
+			backoff = minBackoff
+		}
+		log.Printf("[DISPATCH][ERROR] stream closed: %v, reconnect in %s\n", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// serve - одно соединение: hello и обработка сообщений менеджера до обрыва потока
+func (s *Stream) serve(ctx context.Context, g *generator.Generator) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := s.manager.Dispatch(ctx)
+	if err != nil {
+		return err
+	}
+	c := newConn(stream, s.cfg.ManagerTimeout)
+	err = c.send(&gridpb.SlaveMessage{Msg: &gridpb.SlaveMessage_Hello{Hello: &gridpb.Hello{SlaveUuid: s.cfg.UUID}}})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.conn = c
+	s.mu.Unlock()
+	log.Println("[DISPATCH] stream connected")
+
+	defer func() {
+		s.mu.Lock()
+		if s.conn == c {
+			s.conn = nil
+		}
+		s.mu.Unlock()
+		close(c.done)
+	}()
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return gridpb.Error(err)
+		}
+
+		id := msg.GetId()
+		switch m := msg.GetMsg().(type) {
+		case *gridpb.ManagerMessage_Compute:
+			// блобы подзадачи могут скачиваться у менеджера, поток за это время не должен стоять
+			go func(req protocol.ComputeRequest) {
+				c.reply(&gridpb.SlaveMessage{Id: id, Msg: &gridpb.SlaveMessage_Ack{Ack: gridpb.NewAck(g.AddTask(req))}})
+			}(m.Compute.Protocol())
+		case *gridpb.ManagerMessage_Cancel:
+			c.reply(&gridpb.SlaveMessage{Id: id, Msg: &gridpb.SlaveMessage_Ack{Ack: gridpb.NewAck(g.Cancel(m.Cancel.GetSubtaskUuid()))}})
+		case *gridpb.ManagerMessage_Status:
+			c.reply(&gridpb.SlaveMessage{Id: id, Msg: &gridpb.SlaveMessage_Status{Status: &gridpb.SlaveStatus{Status: g.CheckStatus()}}})
+		case *gridpb.ManagerMessage_Ack:
+			c.deliver(msg)
+		default:
+			log.Printf("[DISPATCH] unexpected message %T\n", m)
+		}
+	}
+}
+
+// current - текущее соединение или nil
+func (s *Stream) current() *conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.conn
+}
+
+// CompleteSubtask - результат подзадачи в поток, без потока - вызовом gRPC
+func (s *Stream) CompleteSubtask(req protocol.CompleteSubtaskRequest) error {
+	if c := s.current(); c != nil {
+		err := c.request(&gridpb.SlaveMessage{Msg: &gridpb.SlaveMessage_Complete{Complete: gridpb.NewCompleteSubtaskRequest(req)}})
+		if !errors.Is(err, errStreamClosed) {
+			return err
+		}
+	}
+
+	return s.manager.CompleteSubtask(req)
+}
+
+// ErrorSubtask - ошибка подзадачи в поток, без потока - вызовом gRPC
+func (s *Stream) ErrorSubtask(req protocol.ErrorSubtaskRequest) error {
+	if c := s.current(); c != nil {
+		err := c.request(&gridpb.SlaveMessage{Msg: &gridpb.SlaveMessage_Error{Error: gridpb.NewErrorSubtaskRequest(req)}})
+		if !errors.Is(err, errStreamClosed) {
+			return err
+		}
+	}
+
+	return s.manager.ErrorSubtask(req)
+}
+
+// GetBlob - блобы скачиваются обычным вызовом, чтобы не занимать поток
+func (s *Stream) GetBlob(id string) ([]byte, error) {
+	return s.manager.GetBlob(id)
+}
+
+// conn - одно соединение потока. Запросы слейва и ответы менеджера связываются по id
+type conn struct {
+	stream  gridpb.Manager_DispatchClient
+	sendMu  sync.Mutex    // Send потока не потокобезопасен
+	timeout time.Duration // сколько слейв ждет ack менеджера на результат подзадачи (MANAGER_TIMEOUT)
+
+	mu      sync.Mutex
+	lastID  uint64
+	pending map[uint64]chan *gridpb.ManagerMessage
+
+	done chan struct{} // закрывается при обрыве соединения
+}
+
+func newConn(stream gridpb.Manager_DispatchClient, timeout time.Duration) *conn {
+	return &conn{
+		stream:  stream,
+		timeout: timeout,
+		pending: make(map[uint64]chan *gridpb.ManagerMessage),
+		done:    make(chan struct{}),
+	}
+}
+
+func (c *conn) send(msg *gridpb.SlaveMessage) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	return c.stream.Send(msg)
+}
+
+// reply - ответ на запрос менеджера, id ответа совпадает с id запроса
+func (c *conn) reply(msg *gridpb.SlaveMessage) {
+	if err := c.send(msg); err != nil {
+		log.Println("[DISPATCH][ERROR] send reply:", err)
+	}
+}
+
+// request - сообщение менеджеру и ожидание его ack. Если соединение оборвалось до отправки, errStreamClosed
+func (c *conn) request(msg *gridpb.SlaveMessage) error {
+	reply := make(chan *gridpb.ManagerMessage, 1)
+	c.mu.Lock()
+	c.lastID++
+	msg.Id = c.lastID
+	c.pending[msg.Id] = reply
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, msg.Id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.send(msg); err != nil {
+		return errStreamClosed
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-reply:
+		if r.GetAck() == nil {
+			return errUnexpectedReply
+		}
+		return r.GetAck().Err()
+	case <-c.done:
+		return errors.New("dispatch stream closed before ack")
+	case <-timer.C:
+		return errRequestTimeout
+	}
+}
+
+// deliver - ack менеджера на запрос слейва. Ответ на запрос, который уже не ждут, отбрасывается
+func (c *conn) deliver(msg *gridpb.ManagerMessage) {
+	c.mu.Lock()
+	ch, ok := c.pending[msg.GetId()]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case ch <- msg:
+	default:
+	}
+}
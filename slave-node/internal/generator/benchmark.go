@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"go.starlark.net/starlark"
+	"log"
+	"sync"
+	"time"
+)
+
+const benchmarkIterations = 200000
+
+// benchmarkScript - эталонная нагрузка на интерпретатор Starlark: цикл с арифметикой
+const benchmarkScript = `
+def bench(n):
+    acc = 0
+    for i in range(n):
+        acc = (acc + i * i) % 1000003
+    return acc
+`
+
+var (
+	benchmarkOnce  sync.Once
+	benchmarkScore float64
+)
+
+/*
+Benchmark - оценка производительности ноды: тысячи итераций эталонного скрипта в секунду
+
+Замер выполняется один раз за жизнь процесса: при повторной регистрации слейв уже решает подзадачи,
+и замер под нагрузкой занизил бы оценку
+*/
+func Benchmark() float64 {
+	benchmarkOnce.Do(func() { benchmarkScore = runBenchmark() })
+
+	return benchmarkScore
+}
+
+func runBenchmark() float64 {
+	thread := &starlark.Thread{Name: "benchmark"}
+
+	globals, err := starlark.ExecFile(thread, "benchmark.star", benchmarkScript, nil)
+	if err != nil {
+		log.Println("[BENCHMARK][ERROR]", err)
+		return 0
+	}
+
+	start := time.Now()
+	_, err = starlark.Call(thread, globals["bench"], starlark.Tuple{starlark.MakeInt(benchmarkIterations)}, nil)
+	if err != nil {
+		log.Println("[BENCHMARK][ERROR]", err)
+		return 0
+	}
+	elapsed := time.Since(start)
+
+	score := float64(benchmarkIterations) / elapsed.Seconds() / 1000
+	log.Printf("[BENCHMARK] %d iterations in %s, score: %.2f\n", benchmarkIterations, elapsed, score)
+
+	return score
+}
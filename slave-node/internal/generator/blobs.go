@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"protocol"
+	"sync"
+)
+
+const (
+	defaultBlobCacheSize  = 64
+	defaultBlobCacheBytes = 256 << 20
+)
+
+// BlobID - id блоба по его содержимому (sha256), совпадает с id на менеджере
+func BlobID(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// blobEntry - блоб в кэше слейва: скрипт или входные данные задачи
+type blobEntry struct {
+	id     string
+	data   []byte
+	script compiledScript // заполняется при первом исполнении блоба как скрипта
+}
+
+// blobFetch - скачивание блоба, которое ждут параллельные подзадачи
+type blobFetch struct {
+	done  chan struct{}
+	entry *blobEntry
+	err   error
+}
+
+/*
+blobCache - LRU кэш блобов по id. Каждый блоб скачивается у менеджера один раз
+
+Кэш ограничен и кол-вом блобов, и их суммарным размером: давно не используемые вытесняются, пока оба лимита
+не соблюдены. Последний положенный блоб остается, даже если он один больше лимита - он нужен подзадаче
+*/
+type blobCache struct {
+	size     int   // максимум блобов
+	maxBytes int64 // максимум суммарного размера блобов
+	bytes    int64 // текущий суммарный размер
+	entries  map[string]*list.Element
+	order    *list.List
+	fetching map[string]*blobFetch
+	fetch    func(id string) ([]byte, error)
+	mu       sync.Mutex
+}
+
+func newBlobCache(size int, maxBytes int64, fetch func(id string) ([]byte, error)) *blobCache {
+	if size <= 0 {
+		size = defaultBlobCacheSize
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultBlobCacheBytes
+	}
+
+	return &blobCache{
+		size:     size,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		fetching: make(map[string]*blobFetch),
+		fetch:    fetch,
+	}
+}
+
+// resolve - заполняет скрипты и данные подзадачи из кэша, если пришли только их id, а присланные целиком кладет в кэш
+func (c *blobCache) resolve(task *protocol.ComputeRequest) error {
+	for _, script := range []*protocol.ScriptConfig{&task.Generate, &task.Compute} {
+		if script.Script != "" {
+			script.Hash = c.put([]byte(script.Script)).id
+			continue
+		}
+		if script.Hash == "" {
+			continue
+		}
+		e, err := c.get(script.Hash)
+		if err != nil {
+			log.Printf("[BLOB][ERROR] script %s: %v\n", script.Hash, err)
+			return protocol.ErrBlobNotCached
+		}
+		script.Script = string(e.data)
+	}
+
+	if task.DataRef != "" && (len(task.Data) == 0 || string(task.Data) == "null") {
+		e, err := c.get(task.DataRef)
+		if err != nil {
+			log.Printf("[BLOB][ERROR] data %s: %v\n", task.DataRef, err)
+			return protocol.ErrBlobNotCached
+		}
+		task.Data = e.data
+	}
+
+	return nil
+}
+
+// get - блоб из кэша, при промахе скачивается у менеджера
+func (c *blobCache) get(id string) (*blobEntry, error) {
+	c.mu.Lock()
+	if el, ok := c.entries[id]; ok {
+		c.order.MoveToFront(el)
+		c.mu.Unlock()
+		return el.Value.(*blobEntry), nil
+	}
+	if f, ok := c.fetching[id]; ok {
+		c.mu.Unlock()
+		<-f.done
+		return f.entry, f.err
+	}
+	f := &blobFetch{done: make(chan struct{})}
+	c.fetching[id] = f
+	c.mu.Unlock()
+
+	data, err := c.fetch(id)
+	if err == nil && BlobID(data) != id {
+		err = fmt.Errorf("blob %s content mismatch", id)
+	}
+	if err == nil {
+		f.entry = c.put(data)
+	}
+	f.err = err
+
+	c.mu.Lock()
+	delete(c.fetching, id)
+	c.mu.Unlock()
+	close(f.done)
+
+	return f.entry, f.err
+}
+
+func (c *blobCache) put(data []byte) *blobEntry {
+	id := BlobID(data)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*blobEntry)
+	}
+
+	e := &blobEntry{id: id, data: data}
+	c.entries[id] = c.order.PushFront(e)
+	c.bytes += int64(len(data))
+	for c.order.Len() > 1 && (c.order.Len() > c.size || c.bytes > c.maxBytes) {
+		oldest := c.order.Remove(c.order.Back()).(*blobEntry)
+		delete(c.entries, oldest.id)
+		c.bytes -= int64(len(oldest.data))
+	}
+
+	return e
+}
+
+// script - блоб со скриптом подзадачи. Если он успел вытесниться из кэша, кладется заново
+func (c *blobCache) script(script protocol.ScriptConfig) *blobEntry {
+	return c.put([]byte(script.Script))
+}
+
+// fetchBlob - скачивание блоба у менеджера
+func (g *Generator) fetchBlob(id string) ([]byte, error) {
+	return g.manager.GetBlob(id)
+}
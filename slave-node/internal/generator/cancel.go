@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"fmt"
+	"go.starlark.net/starlark"
+	"log"
+)
+
+// runningTask - подзадача, принятая воркерами: в очереди или в решении
+type runningTask struct {
+	thread    *starlark.Thread // текущий Starlark поток подзадачи, nil пока она в очереди
+	cancelled bool
+	timedOut  bool
+}
+
+// Cancel - отмена подзадачи: решаемый скрипт прерывается через starlark.Thread.Cancel, а подзадача из очереди не будет запущена
+func (g *Generator) Cancel(subtaskUuid string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rt, ok := g.running[subtaskUuid]
+	if !ok {
+		return fmt.Errorf("subtask %s not found", subtaskUuid)
+	}
+	rt.cancelled = true
+	if rt.thread != nil {
+		rt.thread.Cancel("subtask cancelled")
+	}
+	log.Printf("[CANCEL] subtask %s cancelled\n", subtaskUuid)
+
+	return nil
+}
+
+// attachThread - привязка Starlark потока к подзадаче, чтобы его можно было прервать. Поток отмененной подзадачи прерывается сразу
+func (g *Generator) attachThread(subtaskUuid string, thread *starlark.Thread) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rt, ok := g.running[subtaskUuid]
+	if !ok {
+		return
+	}
+	rt.thread = thread
+	if rt.cancelled {
+		thread.Cancel("subtask cancelled")
+	}
+	if rt.timedOut {
+		thread.Cancel("execution timeout")
+	}
+}
+
+func (g *Generator) isCancelled(subtaskUuid string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rt, ok := g.running[subtaskUuid]
+	return ok && rt.cancelled
+}
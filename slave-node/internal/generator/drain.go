@@ -0,0 +1,26 @@
+package generator
+
+import "context"
+
+/*
+Drain - перевод слейва в режим остановки: новые подзадачи отклоняются, а принятые дорешиваются.
+Возвращается, когда результаты всех принятых подзадач отправлены менеджеру, или по ctx
+*/
+func (g *Generator) Drain(ctx context.Context) error {
+	g.mu.Lock()
+	g.draining = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,284 +1,262 @@
 package generator
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"go.starlark.net/starlark"
-	"io/ioutil"
 	"log"
-	"net/http"
+	"protocol"
+	"protocol/starlarkdata"
+	"runtime"
 	"slave-node/internal/config"
 	"slave-node/internal/utils"
-	"slave-node/pkg/model"
+	"sort"
 	"sync"
 )
 
-//type ScriptConfig struct {
-//	Script   string `json:"Script"`
-//	FuncName string `json:"FuncName"`
-//}
-
 var statusStr = []string{
 	"waiting task",
 	"solving",
 	"error",
+	"draining",
 }
 
 const (
 	STATUS_WAIT_TASK uint8 = iota
 	STATUS_SOLVING
 	STATUS_ERROR
+	STATUS_DRAINING
 )
 
+// Manager - методы менеджера, нужные генератору: отправка результатов подзадач и скачивание блобов
+type Manager interface {
+	CompleteSubtask(req protocol.CompleteSubtaskRequest) error
+	ErrorSubtask(req protocol.ErrorSubtaskRequest) error
+	GetBlob(id string) ([]byte, error)
+}
+
 type Generator struct {
-	status uint8
-	cfg    *config.Config
+	status  uint8
+	cfg     *config.Config
+	workers int // кол-во параллельных воркеров (слотов)
+	busy    int // кол-во занятых воркеров
+
+	running map[string]*runningTask // принятые подзадачи по uuid
+	blobs   *blobCache              // скрипты и данные задач по id блоба
+	manager Manager
 
-	taskCh chan model.ComputeRequest
+	draining bool           // слейв останавливается и не принимает новые подзадачи
+	pending  sync.WaitGroup // принятые подзадачи, результат которых еще не отправлен
+
+	taskCh chan protocol.ComputeRequest
 	mu     sync.Mutex
 }
 
-func NewGenerator(cfg *config.Config) *Generator {
+// NewGenerator - генератор слейва. manager - клиент менеджера по транспорту слейва или поток Dispatch
+func NewGenerator(cfg *config.Config, manager Manager) *Generator {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
 
 	g := &Generator{
-		status: 0,
-		cfg:    cfg,
-		taskCh: make(chan model.ComputeRequest),
-		mu:     sync.Mutex{},
+		status:  0,
+		cfg:     cfg,
+		workers: workers,
+		running: make(map[string]*runningTask),
+		manager: manager,
+		taskCh:  make(chan protocol.ComputeRequest, workers),
+		mu:      sync.Mutex{},
+	}
+	g.blobs = newBlobCache(cfg.BlobCacheSize, cfg.BlobCacheBytes, g.fetchBlob)
+	for i := 0; i < workers; i++ {
+		go g.taskWorker(i)
 	}
-	go g.taskWorker()
 	return g
 }
 
-func (g *Generator) AddTask(task model.ComputeRequest) error {
-	if g.status != STATUS_WAIT_TASK {
-		g.mu.Lock()
-		defer g.mu.Unlock()
-		return fmt.Errorf("NODE has status: %s", statusStr[g.status])
+// AddTask - постановка подзадачи в свободный слот. Если свободных слотов нет, подзадача отклоняется
+func (g *Generator) AddTask(task protocol.ComputeRequest) error {
+	// вместо скриптов и данных могут прийти только id блобов, тогда они берутся из кэша или скачиваются у менеджера
+	if err := g.blobs.resolve(&task); err != nil {
+		return err
 	}
 
+	g.mu.Lock()
+	if g.draining {
+		g.mu.Unlock()
+		return protocol.ErrDraining
+	}
+	if g.busy >= g.workers {
+		g.mu.Unlock()
+		return fmt.Errorf("NODE has status: %s, busy slots: %d/%d", statusStr[STATUS_SOLVING], g.busy, g.workers)
+	}
+	g.busy++
+	g.status = STATUS_SOLVING
+	g.running[task.UuidSubtask] = &runningTask{}
+	g.pending.Add(1)
+	g.mu.Unlock()
+
 	g.taskCh <- task
 	return nil
 }
 
+// CheckStatus - "waiting task", пока есть хотя бы один свободный слот
 func (g *Generator) CheckStatus() string {
-	return statusStr[g.status]
-}
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-// Обновленный обработчик задач
-func (g *Generator) taskWorker() {
-	for task := range g.taskCh {
-		log.Println("REQUEST TASK:", task.Start, task.Amount, task.UuidSubtask)
-		g.mu.Lock()
-		g.status = STATUS_SOLVING
-		g.mu.Unlock()
-		data, status, err := g.ComputeTask(task)
-		if err != nil {
-			status = "error"
-			log.Println("ERROR TASK:", data, status, err)
-			err = g.SendAlert(ErrorSubtaskReq{
-				SlaveUUID:   g.cfg.UUID,
-				SubtaskUUID: task.UuidSubtask,
-				Error:       err.Error(),
-			})
-			g.mu.Lock()
-			g.status = STATUS_ERROR
-			g.mu.Unlock()
-
-		} else {
-			log.Println("SEND RESULT TASK:", data, status)
-			err = g.SendResult(task, data, status)
-			g.mu.Lock()
-			g.status = STATUS_WAIT_TASK
-			g.mu.Unlock()
-			if err != nil {
-				g.status = STATUS_ERROR
-			} else {
-				g.mu.Lock()
-				g.status = STATUS_WAIT_TASK
-				g.mu.Unlock()
-			}
+	return g.checkStatus()
+}
 
-		}
+// Heartbeat - состояние слейва для heartbeat менеджеру
+func (g *Generator) Heartbeat() protocol.Heartbeat {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-		g.status = STATUS_WAIT_TASK
+	subtasks := make([]string, 0, len(g.running))
+	for uuid := range g.running {
+		subtasks = append(subtasks, uuid)
+	}
+	sort.Strings(subtasks)
 
+	return protocol.Heartbeat{
+		UUID:     g.cfg.UUID,
+		Kind:     protocol.NODE_KIND_SLAVE,
+		Status:   g.checkStatus(),
+		Subtasks: subtasks,
+		Load:     g.busy,
+		Version:  config.Version,
 	}
 }
 
-// Обновленный метод SendResult
-func (g *Generator) SendResult(task model.ComputeRequest, data interface{}, status string) error {
-	dataBytes, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("Failed to marshal data: %v", err)
-		return fmt.Errorf("Failed to marshal data: %v", err)
-	}
+// Draining - слейв останавливается
+func (g *Generator) Draining() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	result := CompleteSubtaskRequest{
-		SlaveUUID:   g.cfg.UUID,       // Предполагается наличие поля
-		SubtaskUUID: task.UuidSubtask, // Из исходной задачи
-		Status:      status,
-		Data:        json.RawMessage(dataBytes),
+	return g.draining
+}
+
+// checkStatus - вызывается под g.mu
+func (g *Generator) checkStatus() string {
+	if g.draining {
+		return statusStr[STATUS_DRAINING]
+	}
+	if g.busy < g.workers {
+		return statusStr[STATUS_WAIT_TASK]
 	}
+	return statusStr[g.status]
+}
 
-	client := http.Client{}
+// Slots - кол-во параллельных воркеров
+func (g *Generator) Slots() int {
+	return g.workers
+}
 
-	dataRes, err := json.Marshal(result)
-	if err != nil {
-		log.Printf("Failed to marshal data: %v", err)
-		return fmt.Errorf("Failed to marshal data: %v", err)
-	}
+// FreeSlots - кол-во свободных воркеров
+func (g *Generator) FreeSlots() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", g.cfg.ManagerURL, "/api/v1/subtask/complete"), bytes.NewReader(dataRes))
-	if err != nil {
-		log.Printf("Failed to create request: %v", err)
-		return fmt.Errorf("Failed to create request: %v", err)
-	}
+	return g.workers - g.busy
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("Failed to send request: %v", err)
-		return fmt.Errorf("Failed to send request: %v", err)
-	}
+func (g *Generator) releaseSlot(subtaskUuid string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	if resp.StatusCode/100 != 2 {
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			log.Printf("Failed to read response body: %v", err)
-			return fmt.Errorf("Failed to read response body: %v", err)
-		}
-		defer resp.Body.Close()
-		log.Printf("Response body: %s", string(body))
-		return fmt.Errorf("Failed to send request: %s", string(body))
+	delete(g.running, subtaskUuid)
+	g.busy--
+	if g.busy < g.workers {
+		g.status = STATUS_WAIT_TASK
 	}
-
-	return nil
 }
 
-type ErrorSubtaskReq struct {
-	SlaveUUID   string `json:"SlaveUUID"`
-	SubtaskUUID string `json:"SubtaskUUID"`
-	Error       string `json:"Error"`
+// Обновленный обработчик задач. Каждый воркер решает по одной подзадаче в своем Starlark потоке
+func (g *Generator) taskWorker(id int) {
+	for task := range g.taskCh {
+		g.solve(id, task)
+	}
 }
 
-// SendAlert
-func (g *Generator) SendAlert(reqBody ErrorSubtaskReq) error {
+// solve - решение подзадачи и отправка результата менеджеру
+func (g *Generator) solve(id int, task protocol.ComputeRequest) {
+	defer g.pending.Done()
+
+	if g.isCancelled(task.UuidSubtask) {
+		log.Printf("[WORKER %d] SKIP CANCELLED TASK: %s\n", id, task.UuidSubtask)
+		g.releaseSlot(task.UuidSubtask)
+		return
+	}
 
-	client := http.Client{}
+	log.Printf("[WORKER %d] REQUEST TASK: %d %d %s\n", id, task.Start, task.Amount, task.UuidSubtask)
+	data, status, err := g.ComputeTask(task)
+	cancelled := g.isCancelled(task.UuidSubtask)
+	// слот освобождается до отправки результата, чтобы менеджер получил актуальное кол-во свободных слотов
+	g.releaseSlot(task.UuidSubtask)
+	if cancelled {
+		// менеджер сам отменил подзадачу, результат ему не нужен
+		log.Printf("[WORKER %d] TASK CANCELLED: %s\n", id, task.UuidSubtask)
+		return
+	}
+	if err != nil {
+		status = "error"
+		log.Printf("[WORKER %d] ERROR TASK: %v %s %v\n", id, data, status, err)
+		err = g.SendAlert(protocol.ErrorSubtaskRequest{
+			SlaveUUID:   g.cfg.UUID,
+			SubtaskUUID: task.UuidSubtask,
+			Type:        errorType(err),
+			Error:       err.Error(),
+		})
+	} else {
+		log.Printf("[WORKER %d] SEND RESULT TASK: %v %s\n", id, data, status)
+		err = g.SendResult(task, data, status)
+	}
+	if err != nil {
+		log.Printf("[WORKER %d] send error: %v\n", id, err)
+	}
+}
 
-	dataRes, err := json.Marshal(reqBody)
+// SendResult - отправка результата подзадачи менеджеру
+func (g *Generator) SendResult(task protocol.ComputeRequest, data interface{}, status string) error {
+	dataBytes, err := json.Marshal(data)
 	if err != nil {
 		log.Printf("Failed to marshal data: %v", err)
 		return fmt.Errorf("Failed to marshal data: %v", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s%s", g.cfg.ManagerURL, "/api/v1/subtask/complete"), bytes.NewReader(dataRes))
+	freeSlots := g.FreeSlots()
+	err = g.manager.CompleteSubtask(protocol.CompleteSubtaskRequest{
+		SlaveUUID:   g.cfg.UUID,
+		SubtaskUUID: task.UuidSubtask,
+		Status:      status,
+		Data:        json.RawMessage(dataBytes),
+		FreeSlots:   &freeSlots,
+	})
 	if err != nil {
-		log.Printf("Failed to create request: %v", err)
-		return fmt.Errorf("Failed to create request: %v", err)
+		return fmt.Errorf("Failed to send request: %v", err)
 	}
 
-	resp, err := client.Do(req)
+	return nil
+}
+
+// SendAlert - отправка ошибки подзадачи менеджеру
+func (g *Generator) SendAlert(reqBody protocol.ErrorSubtaskRequest) error {
+	err := g.manager.ErrorSubtask(reqBody)
 	if err != nil {
-		log.Printf("Failed to send request: %v", err)
 		return fmt.Errorf("Failed to send request: %v", err)
 	}
 
-	if resp.StatusCode/100 != 2 {
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			log.Printf("Failed to read response body: %v", err)
-			return fmt.Errorf("Failed to read response body: %v", err)
-		}
-		defer resp.Body.Close()
-		log.Printf("Response body: %s", string(body))
-		return fmt.Errorf("Failed to send request: %s", string(body))
-	}
-
 	return nil
 }
 
-type CompleteSubtaskRequest struct {
-	SlaveUUID   string          `json:"UUID"`
-	SubtaskUUID string          `json:"SubtaskUUID"`
-	Status      string          `json:"Status"`
-	Data        json.RawMessage `json:"Data"`
-}
-
-//func (g *Generator) ComputeTask(task model.ComputeRequest) (interface{}, error) {
-//	// Конвертируем входные данные в Starlark значение
-//	data, err := parseInputData(task.Data)
-//	if err != nil {
-//		return nil, fmt.Errorf("input data error: %v", err)
-//	}
-//
-//	// Создаем окружение с входными данными
-//	builtinsGenerate := starlark.StringDict{
-//		"input_data": data,
-//		"amount":     starlark.MakeInt(task.Amount),
-//		"start":      starlark.MakeInt(task.Start),
-//		"error":      starlark.None,
-//	}
-//
-//	// Выполняем скрипт
-//	threadGenerate := &starlark.Thread{
-//		Name:  "starlark",
-//		Print: func(_ *starlark.Thread, msg string) { log.Println("[SCRIPT][GENERATE]", msg) },
-//	}
-//
-//	globalsGenerate, err := starlark.ExecFile(threadGenerate, "compute.star", task.Generate.Script, builtinsGenerate)
-//	if err != nil {
-//		return nil, fmt.Errorf("script error: %v", err)
-//	}
-//
-//	argsGenerate := starlark.Tuple{
-//		data,
-//		starlark.MakeInt(task.Amount),
-//		starlark.MakeInt(task.Start),
-//	}
-//
-//	resultGenerate, err := starlark.Call(threadGenerate, globalsGenerate[task.Generate.FuncName], argsGenerate, nil)
-//	if err != nil {
-//		return nil, fmt.Errorf("script error while calling: %v", err)
-//	}
-//
-//	builtinsCompute := starlark.StringDict{
-//		"input_data": resultGenerate,
-//		"error":      starlark.None,
-//	}
-//
-//	threadCompute := &starlark.Thread{
-//		Name:  "starlark",
-//		Print: func(_ *starlark.Thread, msg string) { log.Println("[SCRIPT][COMPUTE]", msg) },
-//	}
-//
-//	globalsCompute, err := starlark.ExecFile(threadCompute, "compute.star", task.Compute.Script, builtinsCompute)
-//	if err != nil {
-//		return nil, fmt.Errorf("script error: %v", err)
-//	}
-//
-//	argsCompute := starlark.Tuple{
-//		data,
-//		starlark.MakeInt(task.Amount),
-//		starlark.MakeInt(task.Start),
-//	}
-//
-//	result, err := starlark.Call(threadCompute, globalsCompute[task.Compute.FuncName], argsCompute, nil)
-//	if err != nil {
-//		return nil, fmt.Errorf("script error while calling: %v", err)
-//	}
-//
-//	return convertToGoType(result)
-//}
-
 // ComputeTask возвращает данные, статус и ошибку
-func (g *Generator) ComputeTask(task model.ComputeRequest) (interface{}, string, error) {
+func (g *Generator) ComputeTask(task protocol.ComputeRequest) (interface{}, string, error) {
 	defer utils.Recovery("COMPUTE TASK")
 
-	// ... [парсинг входных данных] ...
 	// Конвертируем входные данные в Starlark значение
-	data, err := parseInputData(task.Data)
+	data, err := starlarkdata.ParseInput(task.Data)
 	if err != nil {
 		return nil, "error", fmt.Errorf("input data error: %v", err)
 	}
@@ -286,36 +264,39 @@ func (g *Generator) ComputeTask(task model.ComputeRequest) (interface{}, string,
 	// Создаем окружение с входными данными
 	builtinsGenerate := starlark.StringDict{
 		"input_data": data,
-		"amount":     starlark.MakeInt(task.Amount),
-		"start":      starlark.MakeInt(task.Start),
+		"amount":     starlark.MakeUint(uint(task.Amount)),
+		"start":      starlark.MakeUint(uint(task.Start)),
 		"error":      starlark.None,
 	}
 
-	// Выполняем скрипт
-	threadGenerate := &starlark.Thread{
-		Name:  "starlark",
-		Print: func(_ *starlark.Thread, msg string) { log.Println("[SCRIPT][GENERATE]", msg) },
+	// Лимиты выполнения: шаги на каждый поток, время на всю подзадачу, размер результата
+	limits := g.limits(task)
+	if timer := g.startTimeout(task.UuidSubtask, limits); timer != nil {
+		defer timer.Stop()
 	}
 
-	globalsGenerate, err := starlark.ExecFile(threadGenerate, "generator.star", task.Generate.Script, builtinsGenerate)
+	// Выполняем скрипт
+	threadGenerate := g.newThread(task, limits, "[SCRIPT][GENERATE]")
+
+	globalsGenerate, err := g.blobs.script(task.Generate).exec(threadGenerate, "generator.star", builtinsGenerate)
 	if err != nil {
-		return nil, "error", fmt.Errorf("script error: %v", err)
+		return nil, "error", g.scriptError(task, threadGenerate, limits, "script error", err)
 	}
 
 	argsGenerate := starlark.Tuple{
 		data,
-		starlark.MakeInt(task.Amount),
-		starlark.MakeInt(task.Start),
+		starlark.MakeUint(uint(task.Amount)),
+		starlark.MakeUint(uint(task.Start)),
 	}
 
 	// Выполнение скрипта Generate
 	resultGenerate, err := starlark.Call(threadGenerate, globalsGenerate[task.Generate.FuncName], argsGenerate, nil)
 	if err != nil {
-		return nil, "error", fmt.Errorf("generate script error: %v", err)
+		return nil, "error", g.scriptError(task, threadGenerate, limits, "generate script error", err)
 	}
 
 	// Извлекаем статус и данные из Generate
-	dataGenerate, statusGenerate, err := extractStatusAndData(resultGenerate)
+	statusGenerate, dataGenerate, err := starlarkdata.StatusAndData(resultGenerate)
 	if err != nil {
 		return nil, "error", fmt.Errorf("generate result parsing error: %v", err)
 	}
@@ -324,7 +305,7 @@ func (g *Generator) ComputeTask(task model.ComputeRequest) (interface{}, string,
 	case "error":
 		return nil, "error", nil
 	case "empty":
-		goData, err := convertToGoType(dataGenerate)
+		goData, err := starlarkdata.ToGo(dataGenerate)
 		if err != nil {
 			return nil, "error", err
 		}
@@ -340,14 +321,11 @@ func (g *Generator) ComputeTask(task model.ComputeRequest) (interface{}, string,
 		"error":      starlark.None,
 	}
 
-	threadCompute := &starlark.Thread{
-		Name:  "starlark",
-		Print: func(_ *starlark.Thread, msg string) { log.Println("[SCRIPT][COMPUTE]", msg) },
-	}
+	threadCompute := g.newThread(task, limits, "[SCRIPT][COMPUTE]")
 
-	globalsCompute, err := starlark.ExecFile(threadCompute, "compute.star", task.Compute.Script, builtinsCompute)
+	globalsCompute, err := g.blobs.script(task.Compute).exec(threadCompute, "compute.star", builtinsCompute)
 	if err != nil {
-		return nil, "error", fmt.Errorf("script error: %v", err)
+		return nil, "error", g.scriptError(task, threadCompute, limits, "script error", err)
 	}
 
 	argsCompute := starlark.Tuple{
@@ -357,234 +335,25 @@ func (g *Generator) ComputeTask(task model.ComputeRequest) (interface{}, string,
 	// Выполнение скрипта Compute
 	resultCompute, err := starlark.Call(threadCompute, globalsCompute[task.Compute.FuncName], argsCompute, nil)
 	if err != nil {
-		return nil, "error", fmt.Errorf("compute script error: %v", err)
+		return nil, "error", g.scriptError(task, threadCompute, limits, "compute script error", err)
 	}
 
 	// Извлекаем статус и данные из Compute
-	dataCompute, statusCompute, err := extractStatusAndData(resultCompute)
+	statusCompute, dataCompute, err := starlarkdata.StatusAndData(resultCompute)
 	if err != nil {
 		return nil, "error", fmt.Errorf("compute result parsing error: %v", err)
 	}
 
 	// Конвертируем данные в Go-тип
-	goData, err := convertToGoType(dataCompute)
+	goData, err := starlarkdata.ToGo(dataCompute)
 	if err != nil {
 		return nil, "error", err
 	}
 	log.Println(goData)
 
-	return goData, statusCompute, nil
-}
-
-//func (g *Generator) ComputeTask(task model.ComputeRequest) (interface{}, string, error) {
-//	// Конвертируем входные данные
-//	data, err := parseInputData(task.Data)
-//	if err != nil {
-//		return nil, "error", fmt.Errorf("input data error: %v", err)
-//	}
-//
-//	// Выполняем Generate скрипт
-//	resultGenerate, statusGenerate, err := g.executeGenerateScript(task, data)
-//	if statusGenerate != "ok" || err != nil {
-//		return nil, statusGenerate, err
-//	}
-//
-//	// Подготавливаем входные данные для Compute
-//	computeInput := starlark.NewDict(2)
-//	computeInput.SetKey(starlark.String("matrix"), data)
-//	computeInput.SetKey(starlark.String("routes"), resultGenerate)
-//
-//	// Выполняем Compute скрипт
-//	resultCompute, statusCompute, err := g.executeComputeScript(task, computeInput)
-//	if statusCompute != "ok" || err != nil {
-//		return nil, statusCompute, err
-//	}
-//
-//	// Конвертируем финальный результат
-//	goData, err := convertToGoType(resultCompute)
-//	if err != nil {
-//		return nil, "error", fmt.Errorf("result conversion error: %v", err)
-//	}
-//
-//	return goData, "ok", nil
-//}
-//
-//func (g *Generator) executeGenerateScript(task model.ComputeRequest, data starlark.Value) (starlark.Value, string, error) {
-//	builtins := starlark.StringDict{
-//		"input_data": data,
-//		"amount":     starlark.MakeInt(task.Amount),
-//		"start":      starlark.MakeInt(task.Start),
-//		"error":      starlark.None,
-//	}
-//
-//	thread := &starlark.Thread{Name: "generate"}
-//	globals, err := starlark.ExecFile(thread, "generate.star", task.Generate.Script, builtins)
-//	if err != nil {
-//		return nil, "error", err
-//	}
-//
-//	args := starlark.Tuple{data, starlark.MakeInt(task.Amount), starlark.MakeInt(task.Start)}
-//	result, err := starlark.Call(thread, globals[task.Generate.FuncName], args, nil)
-//	if err != nil {
-//		return nil, "error", err
-//	}
-//
-//	return extractStatusAndData(result)
-//}
-//
-//func (g *Generator) executeComputeScript(task model.ComputeRequest, input *starlark.Dict) (starlark.Value, string, error) {
-//	builtins := starlark.StringDict{
-//		"input_data": input,
-//		"error":      starlark.None,
-//	}
-//
-//	thread := &starlark.Thread{Name: "compute"}
-//	globals, err := starlark.ExecFile(thread, "compute.star", task.Compute.Script, builtins)
-//	if err != nil {
-//		return nil, "error", err
-//	}
-//
-//	result, err := starlark.Call(thread, globals[task.Compute.FuncName], starlark.Tuple{input}, nil)
-//	if err != nil {
-//		return nil, "error", err
-//	}
-//
-//	return extractStatusAndData(result)
-//}
-
-func parseInputData(data json.RawMessage) (starlark.Value, error) {
-	var rawData interface{}
-	if err := json.Unmarshal(data, &rawData); err != nil {
-		return nil, err
-	}
-	return toStarlarkValue(rawData)
-}
-
-func toStarlarkValue(v interface{}) (starlark.Value, error) {
-	switch v := v.(type) {
-	case nil:
-		return starlark.None, nil
-	case bool:
-		return starlark.Bool(v), nil
-	case int:
-		return starlark.MakeInt(v), nil
-	case float64:
-		return starlark.Float(v), nil
-	case string:
-		return starlark.String(v), nil
-	case []interface{}:
-		elems := make([]starlark.Value, len(v))
-		for i, elem := range v {
-			val, err := toStarlarkValue(elem)
-			if err != nil {
-				return nil, err
-			}
-			elems[i] = val
-		}
-		return starlark.NewList(elems), nil
-	case map[string]interface{}:
-		dict := starlark.NewDict(len(v))
-		for key, val := range v {
-			starlarkVal, err := toStarlarkValue(val)
-			if err != nil {
-				return nil, err
-			}
-			if err := dict.SetKey(starlark.String(key), starlarkVal); err != nil {
-				return nil, err
-			}
-		}
-		return dict, nil
-	default:
-		return nil, fmt.Errorf("unsupported type: %T", v)
-	}
-}
-
-func convertToGoType(v starlark.Value) (interface{}, error) {
-	switch v := v.(type) {
-	case starlark.NoneType:
-		return nil, nil
-	case starlark.Bool:
-		return bool(v), nil
-	case starlark.Int:
-		if i, ok := v.Int64(); ok {
-			return i, nil
-		}
-		return nil, fmt.Errorf("integer out of range")
-	case starlark.Float:
-		return float64(v), nil
-	case starlark.String:
-		return string(v), nil
-	case *starlark.List:
-		var result []interface{}
-		iter := v.Iterate()
-		defer iter.Done()
-		var elem starlark.Value
-		for iter.Next(&elem) {
-			goVal, err := convertToGoType(elem)
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, goVal)
-		}
-		return result, nil
-	case *starlark.Dict:
-		result := make(map[string]interface{})
-		for _, key := range v.Keys() {
-			keyStr, ok := key.(starlark.String)
-			if !ok {
-				return nil, fmt.Errorf("non-string key in dict")
-			}
-			val, _, _ := v.Get(key)
-			goVal, err := convertToGoType(val)
-			if err != nil {
-				return nil, err
-			}
-			result[string(keyStr)] = goVal
-		}
-		return result, nil
-	default:
-		return nil, fmt.Errorf("unsupported Starlark type: %T", v)
+	if err = checkResultSize(goData, limits); err != nil {
+		return nil, "error", err
 	}
-}
-
-// extractStatusAndData извлекает статус и данные из результата Starlark
-func extractStatusAndData(result starlark.Value) (data starlark.Value, status string, err error) {
-	switch res := result.(type) {
-	case *starlark.Dict:
-		// Извлекаем статус из словаря
-		statusVal, found, err := res.Get(starlark.String("status"))
-		if err != nil || !found {
-			return nil, "", fmt.Errorf("status not found in result dict")
-		}
-		statusStr, ok := statusVal.(starlark.String)
-		if !ok {
-			return nil, "", fmt.Errorf("status is not a string")
-		}
-		status = string(statusStr)
-
-		// Извлекаем данные из словаря
-		dataVal, found, err := res.Get(starlark.String("data"))
-		if err != nil || !found {
-			return nil, "", fmt.Errorf("data not found in result dict")
-		}
-		data = dataVal
-		return data, status, nil
 
-	case starlark.Tuple:
-		if res.Len() != 2 {
-			return nil, "", fmt.Errorf("expected tuple of length 2")
-		}
-		// Статус - первый элемент, данные - второй
-		statusVal, ok := res.Index(0).(starlark.String)
-		if !ok {
-			return nil, "", fmt.Errorf("status in tuple is not a string")
-		}
-		status = string(statusVal)
-		data = res.Index(1)
-		return data, status, nil
-
-	default:
-		// Если результат не содержит статуса, считаем его "ok"
-		return result, "ok", nil
-	}
+	return goData, statusCompute, nil
 }
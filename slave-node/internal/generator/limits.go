@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.starlark.net/starlark"
+	"log"
+	"protocol"
+	"time"
+)
+
+// LimitError - подзадача остановлена из-за превышения лимита
+type LimitError struct {
+	Type string
+	Err  error
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Type, e.Err)
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// errorType - тип ошибки подзадачи для менеджера
+func errorType(err error) string {
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		return limitErr.Type
+	}
+	return protocol.ERROR_SCRIPT
+}
+
+// execLimits - действующие лимиты подзадачи: из запроса, а если не заданы - из конфига слейва
+type execLimits struct {
+	maxSteps       uint64
+	timeout        time.Duration
+	maxResultBytes int
+}
+
+func (g *Generator) limits(task protocol.ComputeRequest) execLimits {
+	l := execLimits{
+		maxSteps:       g.cfg.MaxExecutionSteps,
+		timeout:        g.cfg.ExecutionTimeout,
+		maxResultBytes: g.cfg.MaxResultBytes,
+	}
+	if task.Limits.MaxSteps > 0 {
+		l.maxSteps = task.Limits.MaxSteps
+	}
+	if task.Limits.TimeoutMs > 0 {
+		l.timeout = time.Duration(task.Limits.TimeoutMs) * time.Millisecond
+	}
+	if task.Limits.MaxResultBytes > 0 {
+		l.maxResultBytes = task.Limits.MaxResultBytes
+	}
+
+	return l
+}
+
+// newThread - Starlark поток подзадачи с лимитом шагов, привязанный к подзадаче для отмены и таймаута
+func (g *Generator) newThread(task protocol.ComputeRequest, l execLimits, prefix string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name:  "starlark",
+		Print: func(_ *starlark.Thread, msg string) { log.Println(prefix, msg) },
+	}
+	if l.maxSteps > 0 {
+		thread.SetMaxExecutionSteps(l.maxSteps)
+	}
+	g.attachThread(task.UuidSubtask, thread)
+
+	return thread
+}
+
+// startTimeout - по истечении лимита времени текущий поток подзадачи прерывается
+func (g *Generator) startTimeout(subtaskUuid string, l execLimits) *time.Timer {
+	if l.timeout <= 0 {
+		return nil
+	}
+
+	return time.AfterFunc(l.timeout, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		rt, ok := g.running[subtaskUuid]
+		if !ok {
+			return
+		}
+		rt.timedOut = true
+		if rt.thread != nil {
+			rt.thread.Cancel("execution timeout")
+		}
+	})
+}
+
+// scriptError - ошибка выполнения скрипта с учетом того, не был ли поток остановлен лимитом
+func (g *Generator) scriptError(task protocol.ComputeRequest, thread *starlark.Thread, l execLimits, msg string, err error) error {
+	err = fmt.Errorf("%s: %v", msg, err)
+
+	if l.maxSteps > 0 && thread.ExecutionSteps() >= l.maxSteps {
+		return &LimitError{Type: protocol.ERROR_LIMIT_STEPS, Err: fmt.Errorf("max execution steps %d exceeded: %v", l.maxSteps, err)}
+	}
+	if g.isTimedOut(task.UuidSubtask) {
+		return &LimitError{Type: protocol.ERROR_LIMIT_TIMEOUT, Err: fmt.Errorf("timeout %s exceeded: %v", l.timeout, err)}
+	}
+
+	return err
+}
+
+// checkResultSize - проверка размера результата в JSON
+func checkResultSize(data interface{}, l execLimits) error {
+	if l.maxResultBytes <= 0 {
+		return nil
+	}
+
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if len(dataBytes) > l.maxResultBytes {
+		return &LimitError{Type: protocol.ERROR_LIMIT_RESULT, Err: fmt.Errorf("result size %d bytes exceeds limit %d bytes", len(dataBytes), l.maxResultBytes)}
+	}
+
+	return nil
+}
+
+func (g *Generator) isTimedOut(subtaskUuid string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rt, ok := g.running[subtaskUuid]
+	return ok && rt.timedOut
+}
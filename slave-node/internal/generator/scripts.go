@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+	"sync"
+)
+
+// имена, которые слейв подставляет в окружение скриптов. Значения input_data, amount и start меняются от подзадачи к подзадаче
+var (
+	predeclaredNames = map[string]struct{}{"input_data": {}, "amount": {}, "start": {}, "error": {}}
+	subtaskNames     = map[string]struct{}{"input_data": {}, "amount": {}, "start": {}}
+)
+
+// compiledScript - скомпилированный скрипт. Если модуль не зависит от данных подзадачи,
+// его замороженные глобальные переменные инициализируются один раз и переиспользуются
+type compiledScript struct {
+	once    sync.Once
+	program *starlark.Program
+	pure    bool // модуль не обращается к input_data/amount/start
+	err     error
+
+	globalsMu sync.Mutex
+	globals   starlark.StringDict
+}
+
+func (cs *compiledScript) compile(filename string, src []byte) {
+	cs.once.Do(func() {
+		isPredeclared := func(name string) bool {
+			_, ok := predeclaredNames[name]
+			return ok
+		}
+
+		var f *syntax.File
+		f, cs.program, cs.err = starlark.SourceProgramOptions(&syntax.FileOptions{}, filename, src, isPredeclared)
+		if cs.err != nil {
+			return
+		}
+		cs.pure = !usesSubtaskNames(f)
+	})
+}
+
+// usesSubtaskNames - обращается ли скрипт к значениям подзадачи, подставленным в окружение
+func usesSubtaskNames(f *syntax.File) bool {
+	uses := false
+	syntax.Walk(f, func(n syntax.Node) bool {
+		id, ok := n.(*syntax.Ident)
+		if !ok {
+			return !uses
+		}
+		if b, ok := id.Binding.(*resolve.Binding); ok && b.Scope == resolve.Predeclared {
+			if _, ok := subtaskNames[id.Name]; ok {
+				uses = true
+			}
+		}
+		return !uses
+	})
+
+	return uses
+}
+
+// exec - глобальные переменные скрипта. Чистый модуль исполняется один раз, остальные - на каждую подзадачу
+func (e *blobEntry) exec(thread *starlark.Thread, filename string, predeclared starlark.StringDict) (starlark.StringDict, error) {
+	cs := &e.script
+	cs.compile(filename, e.data)
+	if cs.err != nil {
+		return nil, cs.err
+	}
+
+	if !cs.pure {
+		globals, err := cs.program.Init(thread, predeclared)
+		globals.Freeze()
+		return globals, err
+	}
+
+	cs.globalsMu.Lock()
+	defer cs.globalsMu.Unlock()
+
+	if cs.globals != nil {
+		return cs.globals, nil
+	}
+	globals, err := cs.program.Init(thread, predeclared)
+	if err != nil {
+		// ошибка инициализации (например, лимит или отмена) не кэшируется
+		return nil, err
+	}
+	globals.Freeze()
+	cs.globals = globals
+
+	return globals, nil
+}
@@ -0,0 +1,80 @@
+package pull
+
+import (
+	"context"
+	"errors"
+	"log"
+	"protocol"
+	"protocol/client"
+	"slave-node/internal/config"
+	"slave-node/internal/generator"
+	"time"
+)
+
+const (
+	slotCheckInterval = 200 * time.Millisecond // пауза, если менеджер считает слот слейва свободным раньше, чем освободился воркер
+	minBackoff        = time.Second
+	maxBackoff        = 30 * time.Second
+)
+
+/*
+Run - цикл слейва в режиме pull (DISPATCH_MODE=pull), пока не отменен ctx
+
+Слейв забирает подзадачи у менеджера длинным опросом /subtask/next, пока есть свободный воркер. Когда все
+воркеры заняты или слейв останавливается, опрос продолжается без выдачи подзадач: в его ответе приходят отмены.
+Менеджеру не нужен доступ к слейву, поэтому слейв может работать за NAT. Если менеджер недоступен
+или не знает слейв, опрос повторяется с нарастающей паузой: перерегистрирует слейв heartbeat
+*/
+func Run(ctx context.Context, cfg *config.Config, manager *client.Manager, g *generator.Generator) {
+	backoff := minBackoff
+	for ctx.Err() == nil {
+		free := g.FreeSlots()
+		if g.Draining() {
+			free = 0
+		}
+
+		resp, err := manager.NextSubtask(cfg.UUID, free)
+		if err != nil {
+			if !errors.Is(err, protocol.ErrDraining) {
+				log.Printf("[PULL][ERROR] %v, retry in %s\n", err, backoff)
+			}
+			sleep(ctx, backoff)
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+		backoff = minBackoff
+
+		for _, subtaskUuid := range resp.Cancel {
+			// подзадача могла уже решиться, тогда отменять нечего
+			_ = g.Cancel(subtaskUuid)
+		}
+		if resp.Subtask == nil {
+			if free == 0 && len(resp.Cancel) == 0 {
+				sleep(ctx, slotCheckInterval)
+			}
+			continue
+		}
+		req := *resp.Subtask
+
+		if err = g.AddTask(req); err != nil {
+			// подзадача уже в аренде у слейва, без ошибки менеджер отдаст ее другому только по истечении аренды
+			log.Printf("[PULL][ERROR] subtask %s rejected: %v\n", req.UuidSubtask, err)
+			err = manager.ErrorSubtask(protocol.ErrorSubtaskRequest{
+				SlaveUUID:   cfg.UUID,
+				SubtaskUUID: req.UuidSubtask,
+				Type:        protocol.ERROR_REJECTED,
+				Error:       err.Error(),
+			})
+			if err != nil {
+				log.Println("[PULL][ERROR] send error:", err)
+			}
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -2,10 +2,11 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/valyala/fasthttp"
 	"net/http"
-	"slave-node/pkg/model"
+	"protocol"
 )
 
 func (s *Server) addTask(method string, body []byte, args *fasthttp.Args) error {
@@ -13,13 +14,16 @@ func (s *Server) addTask(method string, body []byte, args *fasthttp.Args) error
 		return errMethodNotAllowed
 	}
 
-	var req model.ComputeRequest
+	var req protocol.ComputeRequest
 
 	if err := json.Unmarshal(body, &req); err != nil {
 		return fmt.Errorf("Invalid request format: %v", err)
 	}
 
 	err := s.generator.AddTask(req)
+	if err == protocol.ErrBlobNotCached || err == protocol.ErrDraining {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("StatusInternalServerError: %v", err)
 	}
@@ -34,3 +38,17 @@ func (s *Server) checkStatus(method string) ([]byte, error) {
 
 	return []byte(s.generator.CheckStatus()), nil
 }
+
+// cancelTask - отмена подзадачи по ее uuid (?subtask=)
+func (s *Server) cancelTask(method string, body []byte, args *fasthttp.Args) error {
+	if method != http.MethodPost {
+		return errMethodNotAllowed
+	}
+
+	subtaskUuid := string(args.Peek("subtask"))
+	if subtaskUuid == "" {
+		return errors.New("subtask uuid is required")
+	}
+
+	return s.generator.Cancel(subtaskUuid)
+}
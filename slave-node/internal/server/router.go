@@ -3,18 +3,20 @@ package server
 import (
 	"errors"
 	"github.com/valyala/fasthttp"
+	"protocol"
 	"slave-node/internal/utils"
 	"strings"
 	"unicode/utf8"
 )
 
+// пути API слейва - из общего протокола
 const (
 	// Generator
-	ADD_TASK_PATH     = "/addTask"
-	CHECK_STATUS_PATH = "/checkStatus"
-	CANCEL_TASK       = "/cancel"
+	ADD_TASK_PATH     = protocol.SLAVE_ADD_TASK_PATH
+	CHECK_STATUS_PATH = protocol.SLAVE_CHECK_STATUS_PATH
+	CANCEL_TASK       = protocol.SLAVE_CANCEL_PATH
 
-	V1 = "/api/v1"
+	V1 = protocol.API_V1
 )
 
 var (
@@ -51,6 +53,8 @@ func (s *Server) Handler(path string, ctx *fasthttp.RequestCtx) {
 		err = s.addTask(method, body, ctx.QueryArgs())
 	case CHECK_STATUS_PATH:
 		resp, err = s.checkStatus(method)
+	case CANCEL_TASK:
+		err = s.cancelTask(method, body, ctx.QueryArgs())
 
 	default:
 		err = errNotFound
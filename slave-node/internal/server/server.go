@@ -6,6 +6,7 @@ import (
 	"github.com/valyala/fasthttp"
 	"log"
 	"net/http"
+	"protocol"
 	"slave-node/internal/config"
 	"slave-node/internal/generator"
 )
@@ -66,6 +67,10 @@ func setStatusCode(ctx *fasthttp.RequestCtx, err error) {
 			ctx.SetStatusCode(fasthttp.StatusNotFound)
 		case errMethodNotAllowed:
 			ctx.SetStatusCode(fasthttp.StatusMethodNotAllowed)
+		case protocol.ErrBlobNotCached:
+			ctx.SetStatusCode(fasthttp.StatusPreconditionFailed)
+		case protocol.ErrDraining:
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
 		default:
 			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
 		}
@@ -76,7 +81,7 @@ func setStatusCode(ctx *fasthttp.RequestCtx, err error) {
 
 func (s *Server) initRoutsServerPrivate() http.Handler {
 	privateMux := http.NewServeMux()
-	privateMux.HandleFunc("/health", func(writer http.ResponseWriter, request *http.Request) { writer.WriteHeader(http.StatusOK) })
+	privateMux.HandleFunc(protocol.HEALTH_PATH, func(writer http.ResponseWriter, request *http.Request) { writer.WriteHeader(http.StatusOK) })
 
 	return privateMux
 }